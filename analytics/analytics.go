@@ -0,0 +1,296 @@
+// Package analytics aggregates scenarios of many prompt sequences to
+// find confusing prompters : error rates, time to answer, most common
+// rejected inputs and where sessions are abandoned.
+//
+// A Collector is fed with the scenario of each ended session, e.g.
+// from the OnSessionEnd hook of the server and strumthttp packages.
+// Inputs of sensitive prompters are already redacted by strumt.
+package analytics
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/antham/strumt/v2"
+)
+
+// DefaultTopRejected is the number of rejected inputs reported by prompter
+const DefaultTopRejected = 5
+
+// DefaultTrackedRejected is the number of distinct rejected inputs
+// tracked by prompter to find the most common ones
+const DefaultTrackedRejected = 100
+
+const (
+	// maxInputLength is the number of characters
+	// of a rejected input which are kept
+	maxInputLength = 64
+	// maxDurations is the number of answer durations
+	// sampled by prompter to compute the median
+	maxDurations = 1000
+)
+
+// Collector aggregates scenarios of ended prompt sequences
+type Collector interface {
+	Collect(scenario []strumt.Step)
+}
+
+// Report gives metrics aggregated from all collected scenarios,
+// Abandoned counts sessions by the prompter they stopped at, including
+// sessions ended by a prompter rejecting inputs
+type Report struct {
+	Sessions  int
+	Completed int
+	Abandoned map[string]int
+	Prompters map[string]PrompterReport
+}
+
+// PrompterReport gives metrics of a single prompter, inputs
+// taken from the environment are not part of answer durations
+type PrompterReport struct {
+	Steps          int
+	Errors         int
+	ErrorRate      float64
+	MedianDuration time.Duration
+	TopRejected    []RejectedInput
+}
+
+// RejectedInput counts how many times inputs were rejected,
+// inputs of a multiline prompter are joined with new lines and
+// inputs longer than 64 characters are truncated
+type RejectedInput struct {
+	Input string
+	Count int
+}
+
+type prompterStats struct {
+	steps     int
+	errors    int
+	answers   int
+	durations []time.Duration
+	rejected  map[string]int
+}
+
+// Memory is a Collector keeping metrics in memory, its size is
+// bounded : answer durations are sampled and only a limited number
+// of distinct rejected inputs are tracked by prompter
+type Memory struct {
+	mu              sync.Mutex
+	topRejected     int
+	trackedRejected int
+	sessions        int
+	completed       int
+	abandoned       map[string]int
+	prompters       map[string]*prompterStats
+}
+
+// NewMemory creates an empty in memory collector
+func NewMemory() *Memory {
+	return &Memory{
+		topRejected:     DefaultTopRejected,
+		trackedRejected: DefaultTrackedRejected,
+		abandoned:       map[string]int{},
+		prompters:       map[string]*prompterStats{},
+	}
+}
+
+// SetTopRejected defines how many of the most common
+// rejected inputs are reported by prompter
+func (m *Memory) SetTopRejected(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.topRejected = n
+}
+
+// SetTrackedRejected defines how many distinct rejected inputs are
+// tracked by prompter, when this number is reached a new input replaces
+// the least rejected one so counts of the most common inputs may be
+// overestimated
+func (m *Memory) SetTrackedRejected(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.trackedRejected = n
+}
+
+// Collect aggregates the scenario of an ended prompt sequence. A session
+// is abandoned when reading inputs failed or when it's collected while
+// a prompter is still waiting for inputs
+func (m *Memory) Collect(scenario []strumt.Step) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions++
+
+	for _, step := range scenario {
+		if step.Aborted() || step.HelpRequested() {
+			continue
+		}
+
+		stats := m.prompter(step.ID())
+		stats.steps++
+
+		if step.Source() != strumt.SourceEnv {
+			stats.sample(step.End().Sub(step.Start()))
+		}
+
+		if step.Error() != nil {
+			stats.errors++
+			stats.reject(truncate(strings.Join(step.Inputs(), "\n")), m.trackedRejected)
+		}
+	}
+
+	if len(scenario) == 0 {
+		m.completed++
+		return
+	}
+
+	switch last := scenario[len(scenario)-1]; {
+	case last.Aborted():
+		m.abandoned[last.ID()]++
+	case last.Next() != "":
+		m.abandoned[last.Next()]++
+	case last.Error() != nil:
+		m.abandoned[last.ID()]++
+	default:
+		m.completed++
+	}
+}
+
+func (m *Memory) prompter(id string) *prompterStats {
+	stats, ok := m.prompters[id]
+
+	if !ok {
+		stats = &prompterStats{rejected: map[string]int{}}
+		m.prompters[id] = stats
+	}
+
+	return stats
+}
+
+// sample keeps a uniform sample of answer durations (reservoir sampling)
+func (s *prompterStats) sample(duration time.Duration) {
+	s.answers++
+
+	if len(s.durations) < maxDurations {
+		s.durations = append(s.durations, duration)
+		return
+	}
+
+	if i := rand.Intn(s.answers); i < maxDurations {
+		s.durations[i] = duration
+	}
+}
+
+// reject counts a rejected input, when tracked inputs are already
+// counted the least rejected one is replaced by input which inherits
+// its count (space-saving algorithm)
+func (s *prompterStats) reject(input string, tracked int) {
+	if _, ok := s.rejected[input]; ok || len(s.rejected) < tracked {
+		s.rejected[input]++
+		return
+	}
+
+	if tracked <= 0 {
+		return
+	}
+
+	least, min, found := "", 0, false
+
+	for in, count := range s.rejected {
+		if !found || count < min || (count == min && in < least) {
+			least, min, found = in, count, true
+		}
+	}
+
+	delete(s.rejected, least)
+	s.rejected[input] = min + 1
+}
+
+func truncate(input string) string {
+	if utf8.RuneCountInString(input) <= maxInputLength {
+		return input
+	}
+
+	return string([]rune(input)[:maxInputLength]) + "…"
+}
+
+// Report computes metrics from all collected scenarios
+func (m *Memory) Report() Report {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := Report{
+		Sessions:  m.sessions,
+		Completed: m.completed,
+		Abandoned: map[string]int{},
+		Prompters: map[string]PrompterReport{},
+	}
+
+	for id, count := range m.abandoned {
+		r.Abandoned[id] = count
+	}
+
+	for id, stats := range m.prompters {
+		p := PrompterReport{
+			Steps:          stats.steps,
+			Errors:         stats.errors,
+			MedianDuration: median(stats.durations),
+			TopRejected:    top(stats.rejected, m.topRejected),
+		}
+
+		if stats.steps > 0 {
+			p.ErrorRate = float64(stats.errors) / float64(stats.steps)
+		}
+
+		r.Prompters[id] = p
+	}
+
+	return r
+}
+
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	middle := len(sorted) / 2
+
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	return sorted[middle]
+}
+
+// top returns the n most common inputs, inputs
+// rejected as many times are sorted alphabetically
+func top(rejected map[string]int, n int) []RejectedInput {
+	inputs := []RejectedInput{}
+
+	for input, count := range rejected {
+		inputs = append(inputs, RejectedInput{input, count})
+	}
+
+	sort.Slice(inputs, func(i, j int) bool {
+		if inputs[i].Count != inputs[j].Count {
+			return inputs[i].Count > inputs[j].Count
+		}
+
+		return inputs[i].Input < inputs[j].Input
+	})
+
+	if len(inputs) > n {
+		inputs = inputs[:n]
+	}
+
+	return inputs
+}
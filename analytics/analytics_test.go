@@ -0,0 +1,164 @@
+package analytics
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antham/strumt/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type IntPrompt struct {
+	id   string
+	next string
+}
+
+func (i *IntPrompt) ID() string {
+	return i.id
+}
+
+func (i *IntPrompt) PromptString() string {
+	return "Enter a number"
+}
+
+func (i *IntPrompt) Parse(value string) error {
+	if _, err := strconv.Atoi(value); err != nil {
+		return fmt.Errorf("%s is not a valid number", value)
+	}
+
+	return nil
+}
+
+func (i *IntPrompt) NextOnSuccess(value string) string {
+	return i.next
+}
+
+func (i *IntPrompt) NextOnError(err error) string {
+	return i.id
+}
+
+type PinPrompt struct {
+	IntPrompt
+}
+
+func (p *PinPrompt) Sensitive() bool {
+	return true
+}
+
+func newPrompts(reader io.Reader) strumt.Prompts {
+	p := strumt.NewPromptsFromReaderAndWriter(reader, io.Discard)
+	p.AddLinePrompter(&IntPrompt{"age", "pin"})
+	p.AddLinePrompter(&PinPrompt{IntPrompt{"pin", ""}})
+	p.SetFirst("age")
+
+	return p
+}
+
+// submit gives inputs to a fresh prompt sequence and returns its scenario
+func submit(inputs ...string) []strumt.Step {
+	p := newPrompts(nil)
+	p.Start()
+
+	for _, input := range inputs {
+		p.Submit([]string{input})
+	}
+
+	return p.Scenario()
+}
+
+// read runs a fresh prompt sequence reading the given
+// inputs, it's aborted if inputs end too early
+func read(inputs string) []strumt.Step {
+	p := newPrompts(strings.NewReader(inputs))
+	p.Run()
+
+	return p.Scenario()
+}
+
+func TestMemory(t *testing.T) {
+	m := NewMemory()
+	m.SetTopRejected(2)
+
+	m.Collect(submit("31", "1234"))
+	m.Collect(read("thirty\n31\nabcd\n1234\n"))
+	m.Collect(submit("thirty", "old", "thirty"))
+	m.Collect(read("31\nabcd\n"))
+	m.Collect(submit("x", "31", "1234"))
+
+	r := m.Report()
+
+	assert.Equal(t, 5, r.Sessions)
+	assert.Equal(t, 3, r.Completed)
+	assert.Equal(t, map[string]int{"age": 1, "pin": 1}, r.Abandoned)
+
+	age := r.Prompters["age"]
+	assert.Equal(t, 9, age.Steps)
+	assert.Equal(t, 5, age.Errors)
+	assert.InDelta(t, 5.0/9.0, age.ErrorRate, 0.0001)
+	assert.Equal(t, []RejectedInput{{"thirty", 3}, {"old", 1}}, age.TopRejected)
+	assert.True(t, age.MedianDuration >= 0)
+
+	pin := r.Prompters["pin"]
+	assert.Equal(t, 5, pin.Steps)
+	assert.Equal(t, 2, pin.Errors)
+	assert.Equal(t, []RejectedInput{{strumt.Redacted, 2}}, pin.TopRejected)
+}
+
+type StrictPrompt struct {
+	IntPrompt
+}
+
+func (s *StrictPrompt) NextOnError(err error) string {
+	return ""
+}
+
+func TestMemoryCountsSequencesEndingOnError(t *testing.T) {
+	p := strumt.NewPromptsFromReaderAndWriter(nil, io.Discard)
+	p.AddLinePrompter(&StrictPrompt{IntPrompt{"age", ""}})
+	p.SetFirst("age")
+	p.Start()
+	p.Submit([]string{"thirty"})
+
+	m := NewMemory()
+	m.Collect(p.Scenario())
+	m.Collect(submit("31", "1234"))
+
+	r := m.Report()
+
+	assert.Equal(t, 2, r.Sessions)
+	assert.Equal(t, 1, r.Completed)
+	assert.Equal(t, map[string]int{"age": 1}, r.Abandoned)
+}
+
+func TestMemoryBoundsRejectedInputs(t *testing.T) {
+	m := NewMemory()
+	m.SetTrackedRejected(2)
+
+	m.Collect(submit("a", "a", "a", "b", "c", strings.Repeat("x", 100), "31", "1234"))
+
+	age := m.Report().Prompters["age"]
+	assert.Equal(t, 6, age.Errors)
+	assert.Equal(t, []RejectedInput{{"a", 3}, {strings.Repeat("x", 64) + "…", 3}}, age.TopRejected)
+}
+
+func TestMedian(t *testing.T) {
+	type scenario struct {
+		durations []time.Duration
+		median    time.Duration
+	}
+
+	scenarios := []scenario{
+		{[]time.Duration{}, 0},
+		{[]time.Duration{3 * time.Second}, 3 * time.Second},
+		{[]time.Duration{5 * time.Second, time.Second, 3 * time.Second}, 3 * time.Second},
+		{[]time.Duration{4 * time.Second, time.Second, 3 * time.Second, 10 * time.Second}, 3500 * time.Millisecond},
+	}
+
+	for _, s := range scenarios {
+		assert.Equal(t, s.median, median(s.durations))
+	}
+}
@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// DefaultNamespace prefixes metric names
+const DefaultNamespace = "strumt"
+
+// Prometheus is a Collector exposing metrics with the Prometheus
+// text exposition format. Rejected inputs are not exposed since they
+// would make label values unbounded and may contain personal data,
+// they are only available from Report
+type Prometheus struct {
+	*Memory
+	namespace string
+}
+
+// NewPrometheus creates an empty Prometheus collector
+func NewPrometheus() *Prometheus {
+	return &Prometheus{Memory: NewMemory(), namespace: DefaultNamespace}
+}
+
+// SetNamespace defines the prefix of metric names
+func (p *Prometheus) SetNamespace(namespace string) {
+	p.namespace = namespace
+}
+
+// WriteTo writes metrics with the Prometheus text exposition format
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	r := p.Report()
+	b := bytes.Buffer{}
+
+	ids := []string{}
+
+	for id := range r.Prompters {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	p.metric(&b, "sessions_total", "counter", "Number of ended sessions.")
+	fmt.Fprintf(&b, "%s_sessions_total %d\n", p.namespace, r.Sessions)
+
+	p.metric(&b, "sessions_completed_total", "counter", "Number of sessions which reached the end of the prompt sequence.")
+	fmt.Fprintf(&b, "%s_sessions_completed_total %d\n", p.namespace, r.Completed)
+
+	p.metric(&b, "sessions_abandoned_total", "counter", "Number of abandoned sessions by the prompter they stopped at.")
+
+	abandoned := []string{}
+
+	for id := range r.Abandoned {
+		abandoned = append(abandoned, id)
+	}
+
+	sort.Strings(abandoned)
+
+	for _, id := range abandoned {
+		fmt.Fprintf(&b, "%s_sessions_abandoned_total{prompter=\"%s\"} %d\n", p.namespace, escape(id), r.Abandoned[id])
+	}
+
+	p.metric(&b, "prompter_steps_total", "counter", "Number of inputs submitted to a prompter.")
+
+	for _, id := range ids {
+		fmt.Fprintf(&b, "%s_prompter_steps_total{prompter=\"%s\"} %d\n", p.namespace, escape(id), r.Prompters[id].Steps)
+	}
+
+	p.metric(&b, "prompter_errors_total", "counter", "Number of inputs rejected by a prompter.")
+
+	for _, id := range ids {
+		fmt.Fprintf(&b, "%s_prompter_errors_total{prompter=\"%s\"} %d\n", p.namespace, escape(id), r.Prompters[id].Errors)
+	}
+
+	p.metric(&b, "prompter_error_ratio", "gauge", "Ratio of inputs rejected by a prompter.")
+
+	for _, id := range ids {
+		fmt.Fprintf(&b, "%s_prompter_error_ratio{prompter=\"%s\"} %g\n", p.namespace, escape(id), r.Prompters[id].ErrorRate)
+	}
+
+	p.metric(&b, "prompter_answer_median_seconds", "gauge", "Median time taken to answer a prompter.")
+
+	for _, id := range ids {
+		fmt.Fprintf(&b, "%s_prompter_answer_median_seconds{prompter=\"%s\"} %g\n", p.namespace, escape(id), r.Prompters[id].MedianDuration.Seconds())
+	}
+
+	return b.WriteTo(w)
+}
+
+// ServeHTTP implements http.Handler to be scraped by Prometheus
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+func (p *Prometheus) metric(w io.Writer, name string, kind string, help string) {
+	fmt.Fprintf(w, "# HELP %s_%s %s\n", p.namespace, name, help)
+	fmt.Fprintf(w, "# TYPE %s_%s %s\n", p.namespace, name, kind)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,61 @@
+package analytics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheus(t *testing.T) {
+	p := NewPrometheus()
+	p.SetNamespace("wizard")
+	p.Collect(submit("31", "1234"))
+	p.Collect(submit("\"thirty\"", "old\\"))
+	p.Collect(read("31\nabcd\n"))
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+
+	lines := []string{}
+
+	for _, line := range strings.Split(strings.TrimSpace(w.Body.String()), "\n") {
+		// durations depend on the time taken by the test
+		if !strings.HasPrefix(line, "wizard_prompter_answer_median_seconds{") {
+			lines = append(lines, line)
+		}
+	}
+
+	assert.Equal(t, []string{
+		"# HELP wizard_sessions_total Number of ended sessions.",
+		"# TYPE wizard_sessions_total counter",
+		"wizard_sessions_total 3",
+		"# HELP wizard_sessions_completed_total Number of sessions which reached the end of the prompt sequence.",
+		"# TYPE wizard_sessions_completed_total counter",
+		"wizard_sessions_completed_total 1",
+		"# HELP wizard_sessions_abandoned_total Number of abandoned sessions by the prompter they stopped at.",
+		"# TYPE wizard_sessions_abandoned_total counter",
+		`wizard_sessions_abandoned_total{prompter="age"} 1`,
+		`wizard_sessions_abandoned_total{prompter="pin"} 1`,
+		"# HELP wizard_prompter_steps_total Number of inputs submitted to a prompter.",
+		"# TYPE wizard_prompter_steps_total counter",
+		`wizard_prompter_steps_total{prompter="age"} 4`,
+		`wizard_prompter_steps_total{prompter="pin"} 2`,
+		"# HELP wizard_prompter_errors_total Number of inputs rejected by a prompter.",
+		"# TYPE wizard_prompter_errors_total counter",
+		`wizard_prompter_errors_total{prompter="age"} 2`,
+		`wizard_prompter_errors_total{prompter="pin"} 1`,
+		"# HELP wizard_prompter_error_ratio Ratio of inputs rejected by a prompter.",
+		"# TYPE wizard_prompter_error_ratio gauge",
+		`wizard_prompter_error_ratio{prompter="age"} 0.5`,
+		`wizard_prompter_error_ratio{prompter="pin"} 0.5`,
+		"# HELP wizard_prompter_answer_median_seconds Median time taken to answer a prompter.",
+		"# TYPE wizard_prompter_answer_median_seconds gauge",
+	}, lines)
+	assert.Contains(t, w.Body.String(), `wizard_prompter_answer_median_seconds{prompter="age"} `)
+	assert.NotContains(t, w.Body.String(), "thirty")
+}
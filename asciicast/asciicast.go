@@ -0,0 +1,246 @@
+// Package asciicast records prompt sessions as asciinema v2 recordings,
+// see https://docs.asciinema.org/manual/asciicast/v2/
+package asciicast
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antham/strumt/v2"
+)
+
+const (
+	eventOutput = "o"
+	eventInput  = "i"
+)
+
+type header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+type event struct {
+	time time.Duration
+	kind string
+	data string
+}
+
+// Recorder wraps the reader and the writer given to strumt prompts
+// and records everything displayed and typed with its timing
+type Recorder struct {
+	mu         sync.Mutex
+	reader     *bufio.Reader
+	writer     io.Writer
+	width      int
+	height     int
+	title      string
+	echoInput  bool
+	inputDelay time.Duration
+	offset     time.Duration
+	start      time.Time
+	events     []event
+	now        func() time.Time
+	prompts    *strumt.Prompts
+}
+
+// NewRecorder creates a recorder of a terminal having the given size,
+// inputs are echoed as outputs in the recording like a terminal would do
+func NewRecorder(reader io.Reader, writer io.Writer, width int, height int) *Recorder {
+	r := &Recorder{reader: bufio.NewReader(reader), writer: writer, width: width, height: height, echoInput: true, now: time.Now}
+	r.start = r.now()
+
+	return r
+}
+
+// SetTitle defines the title of the recording
+func (r *Recorder) SetTitle(title string) {
+	r.title = title
+}
+
+// SetEchoInput defines whether inputs are recorded as outputs as well,
+// it must be disabled when the reader is a terminal already echoing inputs
+// to the writer
+func (r *Recorder) SetEchoInput(echo bool) {
+	r.echoInput = echo
+}
+
+// SetInputDelay adds a pause before each input in the recording,
+// useful when inputs are not typed by a user but come from a buffer
+func (r *Recorder) SetInputDelay(delay time.Duration) {
+	r.inputDelay = delay
+}
+
+// SetPrompts defines the prompts reading from the recorder, inputs
+// read while a sensitive prompter of prompts is running are recorded
+// as strumt.Redacted. Without it, every input is recorded as typed
+func (r *Recorder) SetPrompts(prompts *strumt.Prompts) {
+	r.prompts = prompts
+}
+
+// Reader returns the reader to give to strumt prompts,
+// it returns at most one line per read so each input
+// is recorded when it's consumed
+func (r *Recorder) Reader() io.Reader {
+	var pending []byte
+
+	return readerFunc(func(p []byte) (int, error) {
+		var err error
+
+		if len(pending) == 0 {
+			var line []byte
+
+			line, err = r.reader.ReadSlice('\n')
+			pending = append(pending, line...)
+
+			if err == bufio.ErrBufferFull {
+				err = nil
+			}
+		}
+
+		n := copy(p, pending)
+		pending = pending[n:]
+
+		if len(pending) > 0 {
+			err = nil
+		}
+
+		if n > 0 {
+			r.record(eventInput, r.redact(p[:n]))
+		}
+
+		return n, err
+	})
+}
+
+// redact replaces data with strumt.Redacted when
+// it's read by a sensitive prompter
+func (r *Recorder) redact(data []byte) []byte {
+	if r.prompts == nil {
+		return data
+	}
+
+	prompt, _ := r.prompts.Prompter(r.prompts.Current())
+
+	if s, ok := prompt.(strumt.Sensitive); !ok || !s.Sensitive() {
+		return data
+	}
+
+	if bytes.HasSuffix(data, []byte("\n")) {
+		return []byte(strumt.Redacted + "\n")
+	}
+
+	return []byte(strumt.Redacted)
+}
+
+// Writer returns the writer to give to strumt prompts
+func (r *Recorder) Writer() io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		n, err := r.writer.Write(p)
+
+		if n > 0 {
+			r.record(eventOutput, p[:n])
+		}
+
+		return n, err
+	})
+}
+
+func (r *Recorder) record(kind string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if kind == eventInput {
+		r.offset += r.inputDelay
+	}
+
+	elapsed := r.now().Sub(r.start) + r.offset
+
+	r.events = append(r.events, event{elapsed, kind, string(data)})
+
+	if kind == eventInput && r.echoInput {
+		r.events = append(r.events, event{elapsed, eventOutput, string(data)})
+	}
+}
+
+// Encode writes the recording in the asciicast v2 format
+func (r *Recorder) Encode(writer io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, err := json.Marshal(header{
+		Version:   2,
+		Width:     r.width,
+		Height:    r.height,
+		Timestamp: r.start.Unix(),
+		Title:     r.title,
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(writer, "%s\n", h); err != nil {
+		return err
+	}
+
+	for _, e := range r.events {
+		d := terminalNewLines(e.data)
+
+		// a terminal receives a carriage return when enter is pressed
+		if e.kind == eventInput {
+			d = strings.Replace(e.data, "\n", "\r", -1)
+		}
+
+		data, err := json.Marshal([]interface{}{e.time.Seconds(), e.kind, d})
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(writer, "%s\n", data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// terminalNewLines converts new lines to what a terminal
+// outputs, otherwise players don't move the cursor back
+// to the beginning of the line
+func terminalNewLines(data string) string {
+	out := make([]byte, 0, len(data))
+
+	for i := 0; i < len(data); i++ {
+		if data[i] == '\n' && (i == 0 || data[i-1] != '\r') {
+			out = append(out, '\r')
+		}
+
+		out = append(out, data[i])
+	}
+
+	return string(out)
+}
+
+type readerFunc func([]byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -0,0 +1,154 @@
+package asciicast
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/antham/strumt/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type StringPrompt struct {
+	store             *string
+	prompt            string
+	currentID         string
+	nextPrompt        string
+	nextPromptOnError string
+}
+
+func (s *StringPrompt) ID() string {
+	return s.currentID
+}
+
+func (s *StringPrompt) PromptString() string {
+	return s.prompt
+}
+
+func (s *StringPrompt) Parse(value string) error {
+	if value == "" {
+		return fmt.Errorf("Empty value given")
+	}
+
+	*(s.store) = value
+
+	return nil
+}
+
+func (s *StringPrompt) NextOnSuccess(value string) string {
+	return s.nextPrompt
+}
+
+func (s *StringPrompt) NextOnError(err error) string {
+	return s.nextPromptOnError
+}
+
+func newClock() func() time.Time {
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return func() time.Time {
+		clock = clock.Add(500 * time.Millisecond)
+		return clock
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var stdout bytes.Buffer
+
+	r := NewRecorder(bytes.NewBufferString("\nBrad\nPitt\n"), &stdout, 80, 24)
+	r.now = newClock()
+	r.start = r.now()
+	r.SetTitle("User")
+	r.SetInputDelay(time.Second)
+
+	var firstName, lastName string
+
+	p := strumt.NewPromptsFromReaderAndWriter(r.Reader(), r.Writer())
+	p.AddLinePrompter(&StringPrompt{&firstName, "Enter your first name", "firstName", "lastName", "firstName"})
+	p.AddLinePrompter(&StringPrompt{&lastName, "Enter your last name", "lastName", "", "lastName"})
+	p.SetFirst("firstName")
+	p.Run()
+
+	var cast bytes.Buffer
+
+	assert.NoError(t, r.Encode(&cast))
+	assert.Equal(t, "Brad", firstName)
+	assert.Equal(t, "Pitt", lastName)
+	assert.Equal(t, "Enter your first name\nEmpty value given\n\nEnter your first name\n\nEnter your last name\n", stdout.String())
+	assert.Equal(t, `{"version":2,"width":80,"height":24,"timestamp":1577836800,"title":"User","env":{"TERM":"xterm-256color"}}
+[0.5,"o","Enter your first name\r\n"]
+[2,"i","\r"]
+[2,"o","\r\n"]
+[2.5,"o","Empty value given\r\n"]
+[3,"o","\r\n"]
+[3.5,"o","Enter your first name\r\n"]
+[5,"i","Brad\r"]
+[5,"o","Brad\r\n"]
+[5.5,"o","\r\n"]
+[6,"o","Enter your last name\r\n"]
+[7.5,"i","Pitt\r"]
+[7.5,"o","Pitt\r\n"]
+`, cast.String())
+}
+
+type PasswordPrompt struct {
+	StringPrompt
+}
+
+func (p *PasswordPrompt) Sensitive() bool {
+	return true
+}
+
+func TestRecorderRedactsSensitiveInputs(t *testing.T) {
+	r := NewRecorder(bytes.NewBufferString("Brad\ns3cr3t\n"), ioutil.Discard, 80, 24)
+	r.now = newClock()
+	r.start = r.now()
+
+	var username, password string
+
+	p := strumt.NewPromptsFromReaderAndWriter(r.Reader(), r.Writer())
+	p.AddLinePrompter(&StringPrompt{&username, "Enter your username", "username", "password", "username"})
+	p.AddLinePrompter(&PasswordPrompt{StringPrompt{&password, "Enter your password", "password", "", "password"}})
+	p.SetFirst("username")
+	r.SetPrompts(&p)
+	p.Run()
+
+	var cast bytes.Buffer
+
+	assert.NoError(t, r.Encode(&cast))
+	assert.Equal(t, "s3cr3t", password)
+	assert.NotContains(t, cast.String(), "s3cr3t")
+	assert.Contains(t, cast.String(), `"i","Brad\r"]`)
+	assert.Contains(t, cast.String(), `"i","********\r"]`)
+	assert.Contains(t, cast.String(), `"o","********\r\n"]`)
+}
+
+func TestRecorderWithoutEcho(t *testing.T) {
+	r := NewRecorder(bytes.NewBufferString("Brad\n"), ioutil.Discard, 80, 24)
+	r.now = newClock()
+	r.start = r.now()
+	r.SetEchoInput(false)
+
+	data, err := io.ReadAll(r.Reader())
+	assert.NoError(t, err)
+	assert.Equal(t, "Brad\n", string(data))
+
+	var cast bytes.Buffer
+
+	assert.NoError(t, r.Encode(&cast))
+	assert.Equal(t, `{"version":2,"width":80,"height":24,"timestamp":1577836800,"env":{"TERM":"xterm-256color"}}
+[0.5,"i","Brad\r"]
+`, cast.String())
+}
+
+func TestRecorderReadsLongLines(t *testing.T) {
+	line := string(bytes.Repeat([]byte("a"), 10000)) + "\n"
+	r := NewRecorder(bytes.NewBufferString(line+"b\n"), ioutil.Discard, 80, 24)
+
+	data, err := io.ReadAll(r.Reader())
+	assert.NoError(t, err)
+	assert.Equal(t, line+"b\n", string(data))
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+var errInterrupted = errors.New("interrupted")
+
+type readResult struct {
+	data []byte
+	err  error
+}
+
+// interruptReader wraps a reader and makes pending reads fail
+// with errInterrupted when a signal is received, so the prompt
+// sequence is aborted instead of the process being killed
+type interruptReader struct {
+	reader    io.Reader
+	interrupt <-chan os.Signal
+	results   chan readResult
+	pending   readResult
+}
+
+func newInterruptReader(reader io.Reader, interrupt <-chan os.Signal) *interruptReader {
+	return &interruptReader{reader: reader, interrupt: interrupt}
+}
+
+func (r *interruptReader) Read(b []byte) (int, error) {
+	if r.results == nil {
+		r.results = make(chan readResult)
+		go r.pump()
+	}
+
+	if len(r.pending.data) == 0 && r.pending.err == nil {
+		select {
+		case r.pending = <-r.results:
+		case <-r.interrupt:
+			return 0, errInterrupted
+		}
+	}
+
+	n := copy(b, r.pending.data)
+	r.pending.data = r.pending.data[n:]
+
+	if len(r.pending.data) == 0 && r.pending.err != nil {
+		return n, r.pending.err
+	}
+
+	return n, nil
+}
+
+func (r *interruptReader) pump() {
+	for {
+		b := make([]byte, 4096)
+		n, err := r.reader.Read(b)
+		r.results <- readResult{b[:n], err}
+
+		if err != nil {
+			return
+		}
+	}
+}
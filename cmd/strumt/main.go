@@ -0,0 +1,173 @@
+// Command strumt runs a questionnaire defined in a JSON file against
+// the terminal and prints collected answers on stdout, prompts are
+// displayed on stderr so the output can be consumed by shell scripts :
+//
+//	eval "$(strumt -format export questionnaire.json)"
+//
+// The exit code is 0 when the questionnaire is completed, 1 when
+// it's aborted (e.g. stdin is closed or Ctrl-C is pressed) and 2 when
+// the questionnaire can't be loaded.
+//
+// With -state, progress is saved to the given file when the
+// questionnaire is aborted and running the same command again
+// resumes it from where it stopped :
+//
+//	strumt -state compliance.state compliance.json
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/antham/strumt/v2"
+)
+
+const (
+	exitOK = iota
+	exitAborted
+	exitError
+)
+
+func main() {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGHUP, syscall.SIGTERM)
+
+	os.Exit(run(os.Args[1:], newInterruptReader(os.Stdin, interrupt), os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("strumt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", formatJSON, "output format : json, env or export")
+	state := flags.String("state", "", "file where progress is saved when the questionnaire is aborted")
+	progress := flags.Bool("progress", false, "display the question number in front of each question")
+	review := flags.Bool("review", false, "review answers at the end and change them")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: strumt [-format json|env|export] [-state file] [-progress] [-review] questionnaire.json\n")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitError
+	}
+
+	switch *format {
+	case formatJSON, formatEnv, formatExport:
+	default:
+		fmt.Fprintf(stderr, "unknown output format %s\n", *format)
+		return exitError
+	}
+
+	file, err := os.Open(flags.Arg(0))
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	defer file.Close()
+
+	q, err := loadQuestionnaire(file)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	p := strumt.NewPromptsFromReaderAndWriter(stdin, stderr)
+	q.Register(&p)
+
+	if *progress {
+		p.SetProgress(strumt.ProgressCounter)
+	}
+
+	p.SetReview(*review)
+
+	if *state != "" {
+		if err := resume(&p, *state); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	}
+
+	p.Run()
+
+	if scenario := p.Scenario(); scenario[len(scenario)-1].Error() != nil {
+		if errors.Is(scenario[len(scenario)-1].Error(), errInterrupted) {
+			fmt.Fprintln(stderr, "")
+		}
+
+		fmt.Fprintln(stderr, "questionnaire aborted")
+
+		if *state != "" {
+			if err := save(&p, *state); err != nil {
+				fmt.Fprintln(stderr, err)
+				return exitError
+			}
+
+			fmt.Fprintf(stderr, "progress saved to %s, run the same command again to resume\n", *state)
+		}
+
+		return exitAborted
+	}
+
+	if *state != "" {
+		if err := os.Remove(*state); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	}
+
+	if err := writeAnswers(stdout, *format, q.Answers(&p)); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// resume restores the progress saved in the state file if it exists
+func resume(p *strumt.Prompts, path string) error {
+	file, err := os.Open(path)
+
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	if err := p.Resume(file); err != nil {
+		return fmt.Errorf("can't resume from %s : %s", path, err)
+	}
+
+	return nil
+}
+
+func save(p *strumt.Prompts, path string) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	if err := p.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
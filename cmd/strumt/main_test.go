@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	type scenario struct {
+		name     string
+		args     []string
+		stdin    string
+		code     int
+		stdout   string
+		stderrOK func(string) bool
+	}
+
+	scenarios := []scenario{
+		{
+			"Output answers as json",
+			[]string{"testdata/questionnaire.json"},
+			"\nJohn\nwhatever\n0\n31\nyes\nfish\n\nserver1\n\nserver2\n.\n",
+			exitOK,
+			"{\n  \"ADMIN\": \"true\",\n  \"AGE\": \"31\",\n  \"NAME\": \"John\",\n  \"SERVER_LIST\": [\n    \"server1\",\n    \"server2\"\n  ],\n  \"SHELL\": \"bash\"\n}\n",
+			func(s string) bool {
+				return strings.Contains(s, "A value is required") &&
+					strings.Contains(s, "whatever is not a valid number") &&
+					strings.Contains(s, "0 must be greater than or equal to 1") &&
+					strings.Contains(s, "fish is not one of bash, zsh") &&
+					strings.Contains(s, "Choose a shell [bash/zsh] (bash)")
+			},
+		},
+		{
+			"Output answers as env",
+			[]string{"-format", "env", "testdata/questionnaire.json"},
+			"John\n31\nno\n",
+			exitOK,
+			"NAME=\"John\"\nAGE=\"31\"\nADMIN=\"false\"\n",
+			func(s string) bool { return true },
+		},
+		{
+			"Display progress",
+			[]string{"-progress", "-format", "env", "testdata/questionnaire.json"},
+			"John\n31\nno\n",
+			exitOK,
+			"NAME=\"John\"\nAGE=\"31\"\nADMIN=\"false\"\n",
+			func(s string) bool {
+				return strings.Contains(s, "[1/5] Enter your name") &&
+					strings.Contains(s, "[3/5] Are you an admin [y/n]")
+			},
+		},
+		{
+			"Show help",
+			[]string{"-format", "env", "testdata/questionnaire.json"},
+			"John\n?\n31\nno\n",
+			exitOK,
+			"NAME=\"John\"\nAGE=\"31\"\nADMIN=\"false\"\n",
+			func(s string) bool {
+				return strings.Contains(s, "Enter your age\nYour age in years\n\nEnter your age\n")
+			},
+		},
+		{
+			"Review answers",
+			[]string{"-review", "-format", "env", "testdata/questionnaire.json"},
+			"John\n31\nyes\nzsh\nserver1\n.\n2\n32\n3\nno\n\n",
+			exitOK,
+			"NAME=\"John\"\nAGE=\"32\"\nADMIN=\"false\"\n",
+			func(s string) bool {
+				return strings.Contains(s, "1. Enter your name : John\n2. Enter your age : 31\n3. Are you an admin [y/n] : yes\n4. Choose a shell [bash/zsh] (bash) : zsh\n5. Give some servers : server1\n") &&
+					strings.Contains(s, "1. Enter your name : John\n2. Enter your age : 32\n3. Are you an admin [y/n] : no\nType")
+			},
+		},
+		{
+			"Output answers as shell exports",
+			[]string{"-format", "export", "testdata/questionnaire.json"},
+			"John O'Brien\n31\nno\n",
+			exitOK,
+			"export NAME='John O'\\''Brien'\nexport AGE='31'\nexport ADMIN='false'\n",
+			func(s string) bool { return true },
+		},
+		{
+			"Abort when input is closed",
+			[]string{"testdata/questionnaire.json"},
+			"John\n",
+			exitAborted,
+			"",
+			func(s string) bool { return strings.Contains(s, "questionnaire aborted") },
+		},
+		{
+			"Unknown format",
+			[]string{"-format", "yaml", "testdata/questionnaire.json"},
+			"",
+			exitError,
+			"",
+			func(s string) bool { return strings.Contains(s, "unknown output format yaml") },
+		},
+		{
+			"Missing questionnaire",
+			[]string{},
+			"",
+			exitError,
+			"",
+			func(s string) bool { return strings.Contains(s, "Usage: strumt") },
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(s.args, bytes.NewBufferString(s.stdin), &stdout, &stderr)
+
+			assert.Equal(t, s.code, code)
+			assert.Equal(t, s.stdout, stdout.String())
+			assert.True(t, s.stderrOK(stderr.String()), stderr.String())
+		})
+	}
+}
+
+func TestLoadQuestionnaireErrors(t *testing.T) {
+	type scenario struct {
+		definition string
+		err        string
+	}
+
+	scenarios := []scenario{
+		{`{"questions":[]}`, "questionnaire must define at least one question"},
+		{`{"questions":[{"prompt":"test"}]}`, "a question has no id"},
+		{`{"questions":[{"id":"a"},{"id":"a"}]}`, "question a is defined twice"},
+		{`{"first":"b","questions":[{"id":"a"}]}`, "first question b doesn't exist"},
+		{`{"questions":[{"id":"a","type":"date"}]}`, "question a has an unknown type date"},
+		{`{"questions":[{"id":"a","type":"choice"}]}`, "question a must define choices"},
+		{`{"questions":[{"id":"a","pattern":"("}]}`, "question a has an invalid pattern : error parsing regexp: missing closing ): `(`"},
+		{`{"questions":[{"id":"a","next":"b"}]}`, "question a references an unknown question b"},
+		{`{"questions":[{"id":"a","var":"X;rm -rf ~;Y"}]}`, "question a has an invalid var X;rm -rf ~;Y, it must match ^[A-Za-z_][A-Za-z0-9_]*$"},
+		{`{"questions":[{"id":"1st"}]}`, "question 1st has an invalid var 1ST, it must match ^[A-Za-z_][A-Za-z0-9_]*$"},
+		{`{"questions":[{"id":"a","unknown":true}]}`, "can't decode questionnaire : json: unknown field \"unknown\""},
+	}
+
+	for _, s := range scenarios {
+		_, err := loadQuestionnaire(bytes.NewBufferString(s.definition))
+
+		assert.EqualError(t, err, s.err)
+	}
+}
+
+func TestRunWithState(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "questionnaire.state")
+	args := []string{"-format", "env", "-state", state, "testdata/questionnaire.json"}
+
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, exitAborted, run(args, bytes.NewBufferString("John\n"), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "progress saved to "+state)
+	assert.FileExists(t, state)
+
+	stdout.Reset()
+	stderr.Reset()
+
+	assert.Equal(t, exitOK, run(args, bytes.NewBufferString("31\nno\n"), &stdout, &stderr))
+	assert.Equal(t, "NAME=\"John\"\nAGE=\"31\"\nADMIN=\"false\"\n", stdout.String())
+	assert.NotContains(t, stderr.String(), "Enter your name")
+	assert.NoFileExists(t, state)
+}
+
+func TestRunWithStateOfAnotherQuestionnaire(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "questionnaire.state")
+	assert.NoError(t, os.WriteFile(state, []byte(`{"version":1,"fingerprint":"whatever"}`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, exitError, run([]string{"-state", state, "testdata/questionnaire.json"}, bytes.NewBufferString(""), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "can't resume from "+state)
+}
+
+func TestRunWithStateOfAnEditedQuestionnaire(t *testing.T) {
+	type scenario struct {
+		name   string
+		edited string
+	}
+
+	original := `{"questions":[{"id":"name","prompt":"Enter your name"},{"id":"admin","prompt":"Are you an admin","type":"bool"},{"id":"age","prompt":"Enter your age"}]}`
+
+	scenarios := []scenario{
+		{
+			"Prompt changed",
+			`{"questions":[{"id":"name","prompt":"Enter your login"},{"id":"admin","prompt":"Are you an admin","type":"bool"},{"id":"age","prompt":"Enter your age"}]}`,
+		},
+		{
+			"Branches changed",
+			`{"questions":[{"id":"name","prompt":"Enter your name"},{"id":"admin","prompt":"Are you an admin","type":"bool","branches":{"false":""}},{"id":"age","prompt":"Enter your age"}]}`,
+		},
+		{
+			"Type changed",
+			`{"questions":[{"id":"name","prompt":"Enter your name","type":"int"},{"id":"admin","prompt":"Are you an admin","type":"bool"},{"id":"age","prompt":"Enter your age"}]}`,
+		},
+		{
+			"Pattern changed",
+			`{"questions":[{"id":"name","prompt":"Enter your name","pattern":"^[a-z]+$"},{"id":"admin","prompt":"Are you an admin","type":"bool"},{"id":"age","prompt":"Enter your age"}]}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			dir := t.TempDir()
+			questionnaire := filepath.Join(dir, "questionnaire.json")
+			state := filepath.Join(dir, "questionnaire.state")
+			args := []string{"-state", state, questionnaire}
+
+			var stdout, stderr bytes.Buffer
+
+			assert.NoError(t, os.WriteFile(questionnaire, []byte(original), 0o600))
+			assert.Equal(t, exitAborted, run(args, bytes.NewBufferString("John\nno\n"), &stdout, &stderr))
+
+			stderr.Reset()
+
+			assert.NoError(t, os.WriteFile(questionnaire, []byte(s.edited), 0o600))
+			assert.Equal(t, exitError, run(args, bytes.NewBufferString("31\n"), &stdout, &stderr))
+			assert.Contains(t, stderr.String(), "can't resume from "+state)
+			assert.Empty(t, stdout.String())
+		})
+	}
+}
+
+func TestRunInterrupted(t *testing.T) {
+	interrupt := make(chan os.Signal, 1)
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	go func() {
+		writer.Write([]byte("John\n"))
+		interrupt <- os.Interrupt
+	}()
+
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, exitAborted, run([]string{"testdata/questionnaire.json"}, newInterruptReader(reader, interrupt), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "questionnaire aborted")
+}
+
+// interruptWriter sends an interrupt once the given text has been written
+type interruptWriter struct {
+	bytes.Buffer
+	text      string
+	interrupt chan<- os.Signal
+}
+
+func (w *interruptWriter) Write(b []byte) (int, error) {
+	n, err := w.Buffer.Write(b)
+
+	if strings.Contains(w.String(), w.text) {
+		select {
+		case w.interrupt <- os.Interrupt:
+		default:
+		}
+	}
+
+	return n, err
+}
+
+func TestRunInterruptedDuringReview(t *testing.T) {
+	interrupt := make(chan os.Signal, 1)
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	go func() {
+		writer.Write([]byte("John\n31\nno\n"))
+	}()
+
+	var stdout bytes.Buffer
+
+	stderr := interruptWriter{text: "press enter to confirm", interrupt: interrupt}
+
+	assert.Equal(t, exitAborted, run([]string{"-review", "testdata/questionnaire.json"}, newInterruptReader(reader, interrupt), &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "questionnaire aborted")
+}
+
+func TestRunWithStateAbortedDuringReview(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "questionnaire.state")
+	args := []string{"-review", "-format", "env", "-state", state, "testdata/questionnaire.json"}
+
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, exitAborted, run(args, bytes.NewBufferString("John\n31\nno\n2\n40\n3\n"), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "progress saved to "+state)
+
+	stdout.Reset()
+	stderr.Reset()
+
+	assert.Equal(t, exitOK, run(args, bytes.NewBufferString("2\n32\n\n"), &stdout, &stderr))
+	assert.Equal(t, "NAME=\"John\"\nAGE=\"32\"\nADMIN=\"false\"\n", stdout.String())
+	assert.True(t, strings.HasPrefix(stderr.String(), "\nReview your answers :\n1. Enter your name : John\n2. Enter your age : 31\n"), stderr.String())
+	assert.NoFileExists(t, state)
+}
+
+func TestRunWithStateRedactsSensitiveQuestions(t *testing.T) {
+	dir := t.TempDir()
+	questionnaire := filepath.Join(dir, "questionnaire.json")
+	state := filepath.Join(dir, "questionnaire.state")
+	assert.NoError(t, os.WriteFile(questionnaire, []byte(`{"questions":[{"id":"token","prompt":"Give a token","sensitive":true},{"id":"name","prompt":"Enter your name"}]}`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, exitAborted, run([]string{"-state", state, questionnaire}, bytes.NewBufferString("s3cr3t\n"), &stdout, &stderr))
+
+	content, err := os.ReadFile(state)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "s3cr3t")
+
+	assert.Equal(t, exitOK, run([]string{"-format", "env", "-state", state, questionnaire}, bytes.NewBufferString("an0th3r\nJohn\n"), &stdout, &stderr))
+	assert.Equal(t, "TOKEN=\"an0th3r\"\nNAME=\"John\"\n", stdout.String())
+}
+
+func TestRunWithStateRedactsSensitiveLists(t *testing.T) {
+	dir := t.TempDir()
+	questionnaire := filepath.Join(dir, "questionnaire.json")
+	state := filepath.Join(dir, "questionnaire.state")
+	assert.NoError(t, os.WriteFile(questionnaire, []byte(`{"questions":[{"id":"keys","prompt":"Give some keys","type":"list","sensitive":true},{"id":"name","prompt":"Enter your name"}]}`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, exitAborted, run([]string{"-state", state, questionnaire}, bytes.NewBufferString("s3cr3t\n.\n"), &stdout, &stderr))
+
+	content, err := os.ReadFile(state)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "s3cr3t")
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	formatJSON   = "json"
+	formatEnv    = "env"
+	formatExport = "export"
+)
+
+func writeAnswers(writer io.Writer, format string, answers []Answer) error {
+	switch format {
+	case formatJSON:
+		return writeJSON(writer, answers)
+	case formatEnv:
+		for _, answer := range answers {
+			fmt.Fprintf(writer, "%s=%s\n", answer.Var, strconv.Quote(strings.Join(answer.Values, "\n")))
+		}
+
+		return nil
+	case formatExport:
+		for _, answer := range answers {
+			fmt.Fprintf(writer, "export %s=%s\n", answer.Var, shellQuote(strings.Join(answer.Values, "\n")))
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown output format %s", format)
+}
+
+func writeJSON(writer io.Writer, answers []Answer) error {
+	datas := map[string]interface{}{}
+
+	for _, answer := range answers {
+		if answer.List {
+			datas[answer.Var] = answer.Values
+			continue
+		}
+
+		datas[answer.Var] = answer.Values[0]
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(datas)
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
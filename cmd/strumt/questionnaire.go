@@ -0,0 +1,432 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/antham/strumt/v2"
+)
+
+const (
+	typeString = "string"
+	typeInt    = "int"
+	typeBool   = "bool"
+	typeChoice = "choice"
+	typeList   = "list"
+)
+
+// varPattern matches names usable as shell variables
+var varPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Questionnaire is the declarative definition of a prompt sequence
+type Questionnaire struct {
+	First     string      `json:"first"`
+	Questions []*Question `json:"questions"`
+}
+
+// Question defines a single prompt of a questionnaire.
+//
+// Var is the name of the output variable, it's derived from ID
+// when not defined and must be a valid shell variable name.
+// Next is the ID of the question asked when the answer is valid,
+// when it's not defined the following question in the file is asked,
+// an empty string ends the questionnaire. Branches overrides Next
+// for specific answers. Terminator is the line ending a list input,
+// an empty line by default. Help is shown when "?" is typed. Answers
+// of a sensitive question are not written to the state file.
+type Question struct {
+	ID         string            `json:"id"`
+	Prompt     string            `json:"prompt"`
+	Type       string            `json:"type"`
+	Var        string            `json:"var"`
+	Required   bool              `json:"required"`
+	Default    string            `json:"default"`
+	Pattern    string            `json:"pattern"`
+	Error      string            `json:"error"`
+	Choices    []string          `json:"choices"`
+	Terminator string            `json:"terminator"`
+	Min        *int              `json:"min"`
+	Max        *int              `json:"max"`
+	Next       *string           `json:"next"`
+	Branches   map[string]string `json:"branches"`
+	Sensitive  bool              `json:"sensitive"`
+	Help       string            `json:"help"`
+
+	pattern *regexp.Regexp
+	next    string
+	value   []string
+}
+
+// Answer is the value collected for a question
+type Answer struct {
+	Var    string
+	Values []string
+	List   bool
+}
+
+func loadQuestionnaire(reader io.Reader) (*Questionnaire, error) {
+	q := Questionnaire{}
+
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&q); err != nil {
+		return nil, fmt.Errorf("can't decode questionnaire : %s", err)
+	}
+
+	if err := q.prepare(); err != nil {
+		return nil, err
+	}
+
+	return &q, nil
+}
+
+func (q *Questionnaire) prepare() error {
+	if len(q.Questions) == 0 {
+		return fmt.Errorf("questionnaire must define at least one question")
+	}
+
+	ids := map[string]bool{}
+
+	for _, question := range q.Questions {
+		if question.ID == "" {
+			return fmt.Errorf("a question has no id")
+		}
+
+		if ids[question.ID] {
+			return fmt.Errorf("question %s is defined twice", question.ID)
+		}
+
+		ids[question.ID] = true
+	}
+
+	if q.First == "" {
+		q.First = q.Questions[0].ID
+	}
+
+	if !ids[q.First] {
+		return fmt.Errorf("first question %s doesn't exist", q.First)
+	}
+
+	for i, question := range q.Questions {
+		if err := question.prepare(); err != nil {
+			return err
+		}
+
+		switch {
+		case question.Next != nil:
+			question.next = *question.Next
+		case i+1 < len(q.Questions):
+			question.next = q.Questions[i+1].ID
+		}
+
+		nexts := []string{question.next}
+
+		for _, next := range question.Branches {
+			nexts = append(nexts, next)
+		}
+
+		for _, next := range nexts {
+			if next != "" && !ids[next] {
+				return fmt.Errorf("question %s references an unknown question %s", question.ID, next)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (q *Question) prepare() error {
+	if q.Type == "" {
+		q.Type = typeString
+	}
+
+	switch q.Type {
+	case typeString, typeInt, typeBool, typeList:
+	case typeChoice:
+		if len(q.Choices) == 0 {
+			return fmt.Errorf("question %s must define choices", q.ID)
+		}
+	default:
+		return fmt.Errorf("question %s has an unknown type %s", q.ID, q.Type)
+	}
+
+	if q.Pattern != "" {
+		pattern, err := regexp.Compile(q.Pattern)
+
+		if err != nil {
+			return fmt.Errorf("question %s has an invalid pattern : %s", q.ID, err)
+		}
+
+		q.pattern = pattern
+	}
+
+	if q.Var == "" {
+		q.Var = strings.ToUpper(regexp.MustCompile(`[^a-zA-Z0-9]+`).ReplaceAllString(q.ID, "_"))
+	}
+
+	if !varPattern.MatchString(q.Var) {
+		return fmt.Errorf("question %s has an invalid var %s, it must match %s", q.ID, q.Var, varPattern)
+	}
+
+	return nil
+}
+
+// Register adds all questions to the given prompts and
+// defines the first one
+func (q *Questionnaire) Register(p *strumt.Prompts) {
+	for _, question := range q.Questions {
+		if question.Type == typeList {
+			p.AddMultilinePrompter(&listPrompt{question})
+			continue
+		}
+
+		p.AddLinePrompter(&linePrompt{question})
+	}
+
+	p.SetFirst(q.First)
+}
+
+// Answers returns answers accepted by p in questions definition order,
+// questions not asked or no longer on the path are ignored
+func (q *Questionnaire) Answers(p *strumt.Prompts) []Answer {
+	answers := []Answer{}
+	accepted := p.Answers()
+
+	for _, question := range q.Questions {
+		if _, ok := accepted[question.ID]; !ok || question.value == nil {
+			continue
+		}
+
+		answers = append(answers, Answer{question.Var, question.value, question.Type == typeList})
+	}
+
+	return answers
+}
+
+func (q *Question) promptString() string {
+	prompt := q.Prompt
+
+	if prompt == "" {
+		prompt = q.ID
+	}
+
+	switch {
+	case q.Type == typeBool:
+		prompt += " [y/n]"
+	case q.Type == typeChoice:
+		prompt += " [" + strings.Join(q.Choices, "/") + "]"
+	}
+
+	if q.Default != "" {
+		prompt += " (" + q.Default + ")"
+	}
+
+	return prompt
+}
+
+func (q *Question) fail(format string, args ...interface{}) error {
+	if q.Error != "" {
+		return fmt.Errorf("%s", q.Error)
+	}
+
+	return fmt.Errorf(format, args...)
+}
+
+func (q *Question) parseValue(value string) (string, error) {
+	if value == "" {
+		value = q.Default
+	}
+
+	if value == "" {
+		if q.Required {
+			return "", q.fail("A value is required")
+		}
+
+		return "", nil
+	}
+
+	if q.pattern != nil && !q.pattern.MatchString(value) {
+		return "", q.fail("%s doesn't match %s", value, q.Pattern)
+	}
+
+	switch q.Type {
+	case typeInt:
+		i, err := strconv.Atoi(value)
+
+		if err != nil {
+			return "", q.fail("%s is not a valid number", value)
+		}
+
+		if q.Min != nil && i < *q.Min {
+			return "", q.fail("%s must be greater than or equal to %d", value, *q.Min)
+		}
+
+		if q.Max != nil && i > *q.Max {
+			return "", q.fail("%s must be lower than or equal to %d", value, *q.Max)
+		}
+	case typeBool:
+		switch strings.ToLower(value) {
+		case "y", "yes", "true":
+			return "true", nil
+		case "n", "no", "false":
+			return "false", nil
+		}
+
+		return "", q.fail("You must answer yes or no")
+	case typeChoice:
+		for _, choice := range q.Choices {
+			if choice == value {
+				return value, nil
+			}
+		}
+
+		return "", q.fail("%s is not one of %s", value, strings.Join(q.Choices, ", "))
+	}
+
+	return value, nil
+}
+
+func (q *Question) nextOnSuccess(value string) string {
+	if next, ok := q.Branches[value]; ok {
+		return next
+	}
+
+	return q.next
+}
+
+func (q *Question) transitions() []string {
+	transitions := []string{q.next}
+
+	for _, next := range q.Branches {
+		transitions = append(transitions, next)
+	}
+
+	return transitions
+}
+
+type linePrompt struct {
+	question *Question
+}
+
+func (l *linePrompt) ID() string {
+	return l.question.ID
+}
+
+func (l *linePrompt) PromptString() string {
+	return l.question.promptString()
+}
+
+func (l *linePrompt) Parse(value string) error {
+	v, err := l.question.parseValue(value)
+
+	if err != nil {
+		return err
+	}
+
+	l.question.value = []string{v}
+
+	return nil
+}
+
+func (l *linePrompt) NextOnSuccess(value string) string {
+	return l.question.nextOnSuccess(l.question.value[0])
+}
+
+func (l *linePrompt) NextOnError(err error) string {
+	return l.question.ID
+}
+
+func (l *linePrompt) Transitions() []string {
+	return l.question.transitions()
+}
+
+func (l *linePrompt) Help() string {
+	return l.question.Help
+}
+
+func (l *linePrompt) Sensitive() bool {
+	return l.question.Sensitive
+}
+
+func (l *linePrompt) Choices() []string {
+	return l.question.Choices
+}
+
+type listPrompt struct {
+	question *Question
+}
+
+func (l *listPrompt) ID() string {
+	return l.question.ID
+}
+
+func (l *listPrompt) PromptString() string {
+	return l.question.promptString()
+}
+
+func (l *listPrompt) Parse(values []string) error {
+	list := []string{}
+
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		v, err := l.question.parseValue(value)
+
+		if err != nil {
+			return err
+		}
+
+		list = append(list, v)
+	}
+
+	if l.question.Required && len(list) == 0 {
+		return l.question.fail("A value is required")
+	}
+
+	if l.question.Min != nil && len(list) < *l.question.Min {
+		return l.question.fail("Give at least %d values", *l.question.Min)
+	}
+
+	if l.question.Max != nil && len(list) > *l.question.Max {
+		return l.question.fail("Give at most %d values", *l.question.Max)
+	}
+
+	l.question.value = list
+
+	return nil
+}
+
+func (l *listPrompt) NextOnSuccess(values []string) string {
+	return l.question.next
+}
+
+func (l *listPrompt) NextOnError(err error) string {
+	return l.question.ID
+}
+
+func (l *listPrompt) Transitions() []string {
+	return l.question.transitions()
+}
+
+func (l *listPrompt) Help() string {
+	return l.question.Help
+}
+
+func (l *listPrompt) Sensitive() bool {
+	return l.question.Sensitive
+}
+
+func (l *listPrompt) Choices() []string {
+	return l.question.Choices
+}
+
+func (l *listPrompt) Terminator() string {
+	return l.question.Terminator
+}
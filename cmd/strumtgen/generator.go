@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	kindString = "string"
+	kindInt    = "int"
+	kindBool   = "bool"
+	kindList   = "[]string"
+)
+
+// structType is an annotated struct type
+type structType struct {
+	Name   string
+	Fields []*field
+}
+
+// field is a struct field asked by a generated prompter
+type field struct {
+	Struct    string
+	Name      string
+	Kind      string
+	ID        string
+	Prompt    string
+	Default   string
+	Required  bool
+	Pattern   string
+	Choices   []string
+	Min       *int
+	Max       *int
+	Env       string
+	Sensitive bool
+	Next      string
+}
+
+// TypeName returns the name of the generated prompter
+func (f *field) TypeName() string {
+	return lowerFirst(f.Struct) + f.Name + "Prompt"
+}
+
+// PatternName returns the name of the generated regexp variable
+func (f *field) PatternName() string {
+	return lowerFirst(f.Struct) + f.Name + "Pattern"
+}
+
+// PromptString returns the prompt displayed with choices and default
+func (f *field) PromptString() string {
+	prompt := f.Prompt
+
+	switch {
+	case f.Kind == kindBool:
+		prompt += " [y/n]"
+	case len(f.Choices) > 0:
+		prompt += " [" + strings.Join(f.Choices, "/") + "]"
+	}
+
+	if f.Default != "" {
+		prompt += " (" + f.Default + ")"
+	}
+
+	return prompt
+}
+
+// generate parses Go files of dir and returns
+// the source of prompters of the given types
+func generate(dir string, typeNames []string) ([]byte, error) {
+	pkg, structs, err := parseDir(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	types := []*structType{}
+
+	for _, name := range typeNames {
+		st, ok := structs[name]
+
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found in %s", name, dir)
+		}
+
+		t, err := newStructType(name, st)
+
+		if err != nil {
+			return nil, err
+		}
+
+		types = append(types, t)
+	}
+
+	b := bytes.Buffer{}
+
+	if err := codeTemplate.Execute(&b, map[string]interface{}{"Package": pkg, "Imports": imports(types), "Types": types}); err != nil {
+		return nil, err
+	}
+
+	return format.Source(b.Bytes())
+}
+
+func parseDir(dir string) (string, map[string]*ast.StructType, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return "", nil, fmt.Errorf("expected a single package in %s, got %d", dir, len(pkgs))
+	}
+
+	var pkg string
+	structs := map[string]*ast.StructType{}
+
+	for name, p := range pkgs {
+		pkg = name
+
+		for _, file := range p.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if spec, ok := n.(*ast.TypeSpec); ok {
+					if st, ok := spec.Type.(*ast.StructType); ok {
+						structs[spec.Name.Name] = st
+					}
+				}
+
+				return true
+			})
+		}
+	}
+
+	return pkg, structs, nil
+}
+
+func newStructType(name string, st *ast.StructType) (*structType, error) {
+	t := &structType{Name: name}
+	ids := map[string]bool{}
+
+	for _, f := range st.Fields.List {
+		if f.Tag == nil || len(f.Names) != 1 {
+			continue
+		}
+
+		tag, err := strconv.Unquote(f.Tag.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		prompt, ok := reflect.StructTag(tag).Lookup("prompt")
+
+		if !ok {
+			continue
+		}
+
+		fd, err := newField(name, f.Names[0].Name, typeString(f.Type), prompt, reflect.StructTag(tag))
+
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s : %s", name, f.Names[0].Name, err)
+		}
+
+		if ids[fd.ID] {
+			return nil, fmt.Errorf("field %s.%s : id %s is used twice", name, fd.Name, fd.ID)
+		}
+
+		ids[fd.ID] = true
+		t.Fields = append(t.Fields, fd)
+	}
+
+	if len(t.Fields) == 0 {
+		return nil, fmt.Errorf("struct type %s has no field with a prompt tag", name)
+	}
+
+	for i := 0; i < len(t.Fields)-1; i++ {
+		t.Fields[i].Next = t.Fields[i+1].ID
+	}
+
+	return t, nil
+}
+
+func newField(structName string, name string, kind string, prompt string, tag reflect.StructTag) (*field, error) {
+	f := &field{Struct: structName, Name: name, Kind: kind, Prompt: prompt, ID: lowerFirst(name)}
+
+	switch kind {
+	case kindString, kindInt, kindBool, kindList:
+	default:
+		return nil, fmt.Errorf("type %s is not supported", kind)
+	}
+
+	if id, ok := tag.Lookup("id"); ok {
+		f.ID = id
+	}
+
+	f.Default = tag.Get("default")
+	f.Pattern = tag.Get("pattern")
+	f.Env = tag.Get("env")
+
+	for key, value := range map[string]*bool{"required": &f.Required, "sensitive": &f.Sensitive} {
+		if s, ok := tag.Lookup(key); ok {
+			b, err := strconv.ParseBool(s)
+
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a boolean", key)
+			}
+
+			*value = b
+		}
+	}
+
+	for key, value := range map[string]**int{"min": &f.Min, "max": &f.Max} {
+		if s, ok := tag.Lookup(key); ok {
+			i, err := strconv.Atoi(s)
+
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a number", key)
+			}
+
+			*value = &i
+		}
+	}
+
+	if choices, ok := tag.Lookup("choices"); ok {
+		f.Choices = strings.Split(choices, ",")
+	}
+
+	if f.Pattern != "" {
+		if _, err := regexp.Compile(f.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern : %s", err)
+		}
+	}
+
+	switch {
+	case len(f.Choices) > 0 && kind != kindString:
+		return nil, fmt.Errorf("choices are only supported by string fields")
+	case (f.Min != nil || f.Max != nil) && kind != kindInt && kind != kindList:
+		return nil, fmt.Errorf("min and max are only supported by int and []string fields")
+	case f.Pattern != "" && kind != kindString && kind != kindList:
+		return nil, fmt.Errorf("pattern is only supported by string and []string fields")
+	case f.Default != "" && kind == kindList:
+		return nil, fmt.Errorf("default is not supported by []string fields")
+	}
+
+	return f, nil
+}
+
+func typeString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return "[]" + typeString(e.Elt)
+		}
+	case *ast.StarExpr:
+		return "*" + typeString(e.X)
+	case *ast.SelectorExpr:
+		return typeString(e.X) + "." + e.Sel.Name
+	}
+
+	return fmt.Sprintf("%T", expr)
+}
+
+// imports returns standard library packages used by the generated code
+func imports(types []*structType) []string {
+	used := map[string]bool{"io": true}
+
+	for _, t := range types {
+		for _, f := range t.Fields {
+			switch f.Kind {
+			case kindInt:
+				used["fmt"] = true
+				used["strconv"] = true
+			case kindBool:
+				used["fmt"] = true
+				used["strings"] = true
+			}
+
+			if f.Required || len(f.Choices) > 0 || f.Min != nil || f.Max != nil {
+				used["fmt"] = true
+			}
+
+			if f.Pattern != "" {
+				used["fmt"] = true
+				used["regexp"] = true
+			}
+		}
+	}
+
+	pkgs := []string{}
+
+	for pkg := range used {
+		pkgs = append(pkgs, pkg)
+	}
+
+	sort.Strings(pkgs)
+
+	return pkgs
+}
+
+// lowerFirst lowers the leading upper case letters of s,
+// e.g. Name gives name and URLPath gives urlPath
+func lowerFirst(s string) string {
+	r := []rune(s)
+
+	for i := range r {
+		if !unicode.IsUpper(r[i]) || (i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1])) {
+			break
+		}
+
+		r[i] = unicode.ToLower(r[i])
+	}
+
+	return string(r)
+}
@@ -0,0 +1,15 @@
+// Package example shows prompters generated by strumtgen
+package example
+
+//go:generate go run github.com/antham/strumt/v2/cmd/strumtgen -type User
+
+// User is asked field by field
+type User struct {
+	Name   string   `prompt:"Enter your name" required:"true"`
+	Age    int      `prompt:"Enter your age" default:"18" min:"1" max:"150"`
+	Admin  bool     `prompt:"Are you an admin" default:"n"`
+	Shell  string   `prompt:"Choose a shell" choices:"bash,zsh" default:"bash"`
+	Emails []string `prompt:"Give some emails" pattern:"^.+@.+$" min:"1" max:"3"`
+	Token  string   `prompt:"Give your API token" id:"apiToken" env:"API_TOKEN" sensitive:"true"`
+	Notes  string
+}
@@ -0,0 +1,322 @@
+// Code generated by strumtgen; DO NOT EDIT.
+
+package example
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/antham/strumt/v2"
+)
+
+// NewUserPrompts creates a prompt sequence asking fields of user
+func NewUserPrompts(reader io.Reader, writer io.Writer, user *User) strumt.Prompts {
+	p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+	p.AddLinePrompter(&userNamePrompt{&user.Name})
+	p.AddLinePrompter(&userAgePrompt{&user.Age})
+	p.AddLinePrompter(&userAdminPrompt{&user.Admin})
+	p.AddLinePrompter(&userShellPrompt{&user.Shell})
+	p.AddMultilinePrompter(&userEmailsPrompt{&user.Emails})
+	p.AddLinePrompter(&userTokenPrompt{&user.Token})
+	p.SetFirst("name")
+
+	return p
+}
+
+type userNamePrompt struct {
+	value *string
+}
+
+func (p *userNamePrompt) ID() string {
+	return "name"
+}
+
+func (p *userNamePrompt) PromptString() string {
+	return "Enter your name"
+}
+
+func (p *userNamePrompt) Parse(value string) error {
+	if value == "" {
+		return fmt.Errorf("A value is required")
+	}
+
+	*(p.value) = value
+
+	return nil
+}
+
+func (p *userNamePrompt) NextOnSuccess(value string) string {
+	return "age"
+}
+
+func (p *userNamePrompt) NextOnError(err error) string {
+	return "name"
+}
+
+func (p *userNamePrompt) Transitions() []string {
+	return []string{"age"}
+}
+
+func (p *userNamePrompt) Value() interface{} {
+	return *(p.value)
+}
+
+type userAgePrompt struct {
+	value *int
+}
+
+func (p *userAgePrompt) ID() string {
+	return "age"
+}
+
+func (p *userAgePrompt) PromptString() string {
+	return "Enter your age (18)"
+}
+
+func (p *userAgePrompt) Parse(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	i, err := strconv.Atoi(value)
+
+	if err != nil {
+		return fmt.Errorf("%s is not a valid number", value)
+	}
+
+	if i < 1 {
+		return fmt.Errorf("%s must be greater than or equal to %d", value, 1)
+	}
+
+	if i > 150 {
+		return fmt.Errorf("%s must be lower than or equal to %d", value, 150)
+	}
+
+	*(p.value) = i
+
+	return nil
+}
+
+func (p *userAgePrompt) NextOnSuccess(value string) string {
+	return "admin"
+}
+
+func (p *userAgePrompt) NextOnError(err error) string {
+	return "age"
+}
+
+func (p *userAgePrompt) Transitions() []string {
+	return []string{"admin"}
+}
+
+func (p *userAgePrompt) Value() interface{} {
+	return *(p.value)
+}
+
+func (p *userAgePrompt) Default() string {
+	return "18"
+}
+
+type userAdminPrompt struct {
+	value *bool
+}
+
+func (p *userAdminPrompt) ID() string {
+	return "admin"
+}
+
+func (p *userAdminPrompt) PromptString() string {
+	return "Are you an admin [y/n] (n)"
+}
+
+func (p *userAdminPrompt) Parse(value string) error {
+	switch strings.ToLower(value) {
+	case "y", "yes", "true":
+		*(p.value) = true
+	case "n", "no", "false":
+		*(p.value) = false
+	default:
+		return fmt.Errorf("You must answer yes or no")
+	}
+
+	return nil
+}
+
+func (p *userAdminPrompt) NextOnSuccess(value string) string {
+	return "shell"
+}
+
+func (p *userAdminPrompt) NextOnError(err error) string {
+	return "admin"
+}
+
+func (p *userAdminPrompt) Transitions() []string {
+	return []string{"shell"}
+}
+
+func (p *userAdminPrompt) Value() interface{} {
+	return *(p.value)
+}
+
+func (p *userAdminPrompt) Default() string {
+	return "n"
+}
+
+type userShellPrompt struct {
+	value *string
+}
+
+func (p *userShellPrompt) ID() string {
+	return "shell"
+}
+
+func (p *userShellPrompt) PromptString() string {
+	return "Choose a shell [bash/zsh] (bash)"
+}
+
+func (p *userShellPrompt) Parse(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	switch value {
+	case "bash", "zsh":
+	default:
+		return fmt.Errorf("%s is not one of %s", value, "bash, zsh")
+	}
+
+	*(p.value) = value
+
+	return nil
+}
+
+func (p *userShellPrompt) NextOnSuccess(value string) string {
+	return "emails"
+}
+
+func (p *userShellPrompt) NextOnError(err error) string {
+	return "shell"
+}
+
+func (p *userShellPrompt) Transitions() []string {
+	return []string{"emails"}
+}
+
+func (p *userShellPrompt) Value() interface{} {
+	return *(p.value)
+}
+
+func (p *userShellPrompt) Default() string {
+	return "bash"
+}
+
+func (p *userShellPrompt) Choices() []string {
+	return []string{"bash", "zsh"}
+}
+
+var userEmailsPattern = regexp.MustCompile(`^.+@.+$`)
+
+type userEmailsPrompt struct {
+	value *[]string
+}
+
+func (p *userEmailsPrompt) ID() string {
+	return "emails"
+}
+
+func (p *userEmailsPrompt) PromptString() string {
+	return "Give some emails"
+}
+
+func (p *userEmailsPrompt) Parse(values []string) error {
+	list := []string{}
+
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		if !userEmailsPattern.MatchString(value) {
+			return fmt.Errorf("%s doesn't match %s", value, "^.+@.+$")
+		}
+
+		list = append(list, value)
+	}
+
+	if len(list) < 1 {
+		return fmt.Errorf("Give at least %d values", 1)
+	}
+
+	if len(list) > 3 {
+		return fmt.Errorf("Give at most %d values", 3)
+	}
+
+	*(p.value) = list
+
+	return nil
+}
+
+func (p *userEmailsPrompt) NextOnSuccess(values []string) string {
+	return "apiToken"
+}
+
+func (p *userEmailsPrompt) NextOnError(err error) string {
+	return "emails"
+}
+
+func (p *userEmailsPrompt) Transitions() []string {
+	return []string{"apiToken"}
+}
+
+func (p *userEmailsPrompt) Value() interface{} {
+	return *(p.value)
+}
+
+type userTokenPrompt struct {
+	value *string
+}
+
+func (p *userTokenPrompt) ID() string {
+	return "apiToken"
+}
+
+func (p *userTokenPrompt) PromptString() string {
+	return "Give your API token"
+}
+
+func (p *userTokenPrompt) Parse(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	*(p.value) = value
+
+	return nil
+}
+
+func (p *userTokenPrompt) NextOnSuccess(value string) string {
+	return ""
+}
+
+func (p *userTokenPrompt) NextOnError(err error) string {
+	return "apiToken"
+}
+
+func (p *userTokenPrompt) Transitions() []string {
+	return []string{""}
+}
+
+func (p *userTokenPrompt) Value() interface{} {
+	return *(p.value)
+}
+
+func (p *userTokenPrompt) Env() string {
+	return "API_TOKEN"
+}
+
+func (p *userTokenPrompt) Sensitive() bool {
+	return true
+}
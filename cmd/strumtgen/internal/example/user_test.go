@@ -0,0 +1,27 @@
+package example
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserPrompts(t *testing.T) {
+	t.Setenv("API_TOKEN", "s3cr3t")
+
+	user := User{}
+	output := bytes.Buffer{}
+
+	p := NewUserPrompts(bytes.NewBufferString("\nJohn\n0\n\nmaybe\ny\nfish\n\nwhatever\n\njohn@example.com\n\n"), &output, &user)
+	p.Run()
+
+	assert.Equal(t, User{Name: "John", Age: 18, Admin: true, Shell: "bash", Emails: []string{"john@example.com"}, Token: "s3cr3t"}, user)
+	assert.Contains(t, output.String(), "A value is required")
+	assert.Contains(t, output.String(), "0 must be greater than or equal to 1")
+	assert.Contains(t, output.String(), "You must answer yes or no")
+	assert.Contains(t, output.String(), "fish is not one of bash, zsh")
+	assert.Contains(t, output.String(), "whatever doesn't match ^.+@.+$")
+	assert.Contains(t, output.String(), "Choose a shell [bash/zsh] (bash)")
+	assert.Equal(t, "s3cr3t", p.Answers()["apiToken"].Value())
+}
@@ -0,0 +1,81 @@
+// Command strumtgen generates prompters from annotated struct types, each
+// field with a prompt tag gets a LinePrompter, or a MultilinePrompter for
+// a []string field, and a constructor wires them into a strumt.Prompts
+// asking fields in order :
+//
+//	//go:generate strumtgen -type User
+//	type User struct {
+//		Name   string   `prompt:"Enter your name" required:"true"`
+//		Age    int      `prompt:"Enter your age" default:"18" min:"1" max:"150"`
+//		Admin  bool     `prompt:"Are you an admin" default:"n"`
+//		Shell  string   `prompt:"Choose a shell" choices:"bash,zsh" default:"bash"`
+//		Emails []string `prompt:"Give some emails" pattern:"^.+@.+$" min:"1"`
+//	}
+//
+// It generates user_prompts.go defining NewUserPrompts(reader, writer, *User).
+// Supported field types are string, int, bool and []string, supported tags are
+// prompt, id, default, required, pattern, choices, min, max, env and sensitive.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("strumtgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	typeNames := flags.String("type", "", "comma-separated list of struct type names")
+	output := flags.String("output", "", "output file name, default <type>_prompts.go, relative to the directory of the types")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: strumtgen -type T[,T...] [-output file] [directory]\n")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *typeNames == "" || flags.NArg() > 1 {
+		flags.Usage()
+		return 2
+	}
+
+	dir := "."
+
+	if flags.NArg() == 1 {
+		dir = flags.Arg(0)
+	}
+
+	types := strings.Split(*typeNames, ",")
+	src, err := generate(dir, types)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "strumtgen: %s\n", err)
+		return 1
+	}
+
+	if *output == "" {
+		*output = strings.ToLower(types[0]) + "_prompts.go"
+	}
+
+	// like stringer, a relative output is relative to the directory of the types
+	if !filepath.IsAbs(*output) {
+		*output = filepath.Join(dir, *output)
+	}
+
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintf(stderr, "strumtgen: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
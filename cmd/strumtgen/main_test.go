@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateExample(t *testing.T) {
+	expected, err := os.ReadFile("internal/example/user_prompts.go")
+	assert.NoError(t, err)
+
+	src, err := generate("internal/example", []string{"User"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(src), "run go generate ./... to update the example")
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "server.go"), []byte("package config\n\ntype Server struct {\n\tHost string `prompt:\"Give a host\"`\n}\n"), 0o644))
+
+	stderr := bytes.Buffer{}
+
+	assert.Equal(t, 0, run([]string{"-type", "Server", dir}, &stderr))
+	assert.FileExists(t, filepath.Join(dir, "server_prompts.go"))
+
+	assert.Equal(t, 0, run([]string{"-type", "Server", "-output", "prompts.go", dir}, &stderr))
+	assert.FileExists(t, filepath.Join(dir, "prompts.go"))
+
+	output := filepath.Join(t.TempDir(), "absolute_prompts.go")
+	assert.Equal(t, 0, run([]string{"-type", "Server", "-output", output, dir}, &stderr))
+	assert.FileExists(t, output)
+
+	assert.Equal(t, 2, run([]string{dir}, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: strumtgen")
+}
+
+func TestGenerateErrors(t *testing.T) {
+	type scenario struct {
+		name   string
+		fields string
+		err    string
+	}
+
+	scenarios := []scenario{
+		{"No prompt", "Name string", "struct type User has no field with a prompt tag"},
+		{"Unsupported type", "Size float64 `prompt:\"Size\"`", "field User.Size : type float64 is not supported"},
+		{"Invalid boolean", "Name string `prompt:\"Name\" required:\"yes please\"`", "field User.Name : required must be a boolean"},
+		{"Invalid number", "Age int `prompt:\"Age\" min:\"one\"`", "field User.Age : min must be a number"},
+		{"Invalid pattern", "Name string `prompt:\"Name\" pattern:\"(\"`", "field User.Name : invalid pattern : error parsing regexp: missing closing ): `(`"},
+		{"Choices of an int", "Age int `prompt:\"Age\" choices:\"1,2\"`", "field User.Age : choices are only supported by string fields"},
+		{"Bounds of a string", "Name string `prompt:\"Name\" max:\"2\"`", "field User.Name : min and max are only supported by int and []string fields"},
+		{"Pattern of a bool", "Admin bool `prompt:\"Admin\" pattern:\"y\"`", "field User.Admin : pattern is only supported by string and []string fields"},
+		{"Default of a list", "Hosts []string `prompt:\"Hosts\" default:\"localhost\"`", "field User.Hosts : default is not supported by []string fields"},
+		{"Duplicated id", "Name string `prompt:\"Name\"`\n\tLogin string `prompt:\"Login\" id:\"name\"`", "field User.Login : id name is used twice"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			dir := t.TempDir()
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "user.go"), []byte("package config\n\ntype User struct {\n\t"+s.fields+"\n}\n"), 0o644))
+
+			_, err := generate(dir, []string{"User"})
+
+			assert.EqualError(t, err, s.err)
+		})
+	}
+
+	_, err := generate(".", []string{"Unknown"})
+	assert.EqualError(t, err, "struct type Unknown not found in .")
+}
+
+func TestLowerFirst(t *testing.T) {
+	for s, expected := range map[string]string{"Name": "name", "URLPath": "urlPath", "ID": "id", "APIToken": "apiToken", "name": "name"} {
+		assert.Equal(t, expected, lowerFirst(s))
+	}
+}
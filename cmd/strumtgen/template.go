@@ -0,0 +1,219 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var codeTemplate = template.Must(template.New("code").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+	"join":  strings.Join,
+	"raw": func(s string) string {
+		if strings.Contains(s, "`") {
+			return strconv.Quote(s)
+		}
+
+		return "`" + s + "`"
+	},
+	"quoteAll": func(values []string) string {
+		quoted := []string{}
+
+		for _, v := range values {
+			quoted = append(quoted, strconv.Quote(v))
+		}
+
+		return strings.Join(quoted, ", ")
+	},
+	"lower": lowerFirst,
+}).Parse(`// Code generated by strumtgen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{quote .}}
+{{- end}}
+
+	"github.com/antham/strumt/v2"
+)
+{{range .Types}}{{$type := .}}
+// New{{.Name}}Prompts creates a prompt sequence asking fields of {{lower .Name}}
+func New{{.Name}}Prompts(reader io.Reader, writer io.Writer, {{lower .Name}} *{{.Name}}) strumt.Prompts {
+	p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+{{- range .Fields}}
+{{- if eq .Kind "[]string"}}
+	p.AddMultilinePrompter(&{{.TypeName}}{&{{lower $type.Name}}.{{.Name}}})
+{{- else}}
+	p.AddLinePrompter(&{{.TypeName}}{&{{lower $type.Name}}.{{.Name}}})
+{{- end}}
+{{- end}}
+	p.SetFirst({{quote (index .Fields 0).ID}})
+
+	return p
+}
+{{range .Fields}}
+{{- if .Pattern}}
+var {{.PatternName}} = regexp.MustCompile({{raw .Pattern}})
+{{end}}
+type {{.TypeName}} struct {
+	value *{{.Kind}}
+}
+
+func (p *{{.TypeName}}) ID() string {
+	return {{quote .ID}}
+}
+
+func (p *{{.TypeName}}) PromptString() string {
+	return {{quote .PromptString}}
+}
+{{if eq .Kind "[]string"}}
+func (p *{{.TypeName}}) Parse(values []string) error {
+	list := []string{}
+
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+{{- if .Pattern}}
+
+		if !{{.PatternName}}.MatchString(value) {
+			return fmt.Errorf("%s doesn't match %s", value, {{quote .Pattern}})
+		}
+{{- end}}
+
+		list = append(list, value)
+	}
+{{- if .Required}}
+
+	if len(list) == 0 {
+		return fmt.Errorf("A value is required")
+	}
+{{- end}}
+{{- if .Min}}
+
+	if len(list) < {{.Min}} {
+		return fmt.Errorf("Give at least %d values", {{.Min}})
+	}
+{{- end}}
+{{- if .Max}}
+
+	if len(list) > {{.Max}} {
+		return fmt.Errorf("Give at most %d values", {{.Max}})
+	}
+{{- end}}
+
+	*(p.value) = list
+
+	return nil
+}
+
+func (p *{{.TypeName}}) NextOnSuccess(values []string) string {
+	return {{quote .Next}}
+}
+{{else}}
+func (p *{{.TypeName}}) Parse(value string) error {
+{{- if .Required}}
+	if value == "" {
+		return fmt.Errorf("A value is required")
+	}
+
+{{- else if ne .Kind "bool"}}
+	if value == "" {
+		return nil
+	}
+
+{{- end}}
+{{- if .Pattern}}
+
+	if !{{.PatternName}}.MatchString(value) {
+		return fmt.Errorf("%s doesn't match %s", value, {{quote .Pattern}})
+	}
+{{- end}}
+{{- if .Choices}}
+
+	switch value {
+	case {{quoteAll .Choices}}:
+	default:
+		return fmt.Errorf("%s is not one of %s", value, {{quote (join .Choices ", ")}})
+	}
+{{- end}}
+{{- if eq .Kind "int"}}
+
+	i, err := strconv.Atoi(value)
+
+	if err != nil {
+		return fmt.Errorf("%s is not a valid number", value)
+	}
+{{- if .Min}}
+
+	if i < {{.Min}} {
+		return fmt.Errorf("%s must be greater than or equal to %d", value, {{.Min}})
+	}
+{{- end}}
+{{- if .Max}}
+
+	if i > {{.Max}} {
+		return fmt.Errorf("%s must be lower than or equal to %d", value, {{.Max}})
+	}
+{{- end}}
+
+	*(p.value) = i
+{{- else if eq .Kind "bool"}}
+{{if .Required}}
+{{end}}	switch strings.ToLower(value) {
+	case "y", "yes", "true":
+		*(p.value) = true
+	case "n", "no", "false":
+		*(p.value) = false
+	default:
+		return fmt.Errorf("You must answer yes or no")
+	}
+{{- else}}
+
+	*(p.value) = value
+{{- end}}
+
+	return nil
+}
+
+func (p *{{.TypeName}}) NextOnSuccess(value string) string {
+	return {{quote .Next}}
+}
+{{end}}
+func (p *{{.TypeName}}) NextOnError(err error) string {
+	return {{quote .ID}}
+}
+
+func (p *{{.TypeName}}) Transitions() []string {
+	return []string{ {{- quote .Next -}} }
+}
+
+func (p *{{.TypeName}}) Value() interface{} {
+	return *(p.value)
+}
+{{- if .Default}}
+
+func (p *{{.TypeName}}) Default() string {
+	return {{quote .Default}}
+}
+{{- end}}
+{{- if .Choices}}
+
+func (p *{{.TypeName}}) Choices() []string {
+	return []string{ {{- quoteAll .Choices -}} }
+}
+{{- end}}
+{{- if .Env}}
+
+func (p *{{.TypeName}}) Env() string {
+	return {{quote .Env}}
+}
+{{- end}}
+{{- if .Sensitive}}
+
+func (p *{{.TypeName}}) Sensitive() bool {
+	return true
+}
+{{- end}}
+{{end}}{{end}}`))
@@ -0,0 +1,81 @@
+package strumt
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const editorErrorPrefix = "# error: "
+
+func (p *Prompts) editor() []string {
+	for _, editor := range []string{p.editorCommand, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if fields := strings.Fields(editor); len(fields) > 0 {
+			return fields
+		}
+	}
+
+	return []string{"vi"}
+}
+
+// readFromEditor writes the content to edit in a temporary file,
+// opens it with an editor and reads the file back once the editor
+// is closed. When the previous attempt of the same prompt failed,
+// the previous content is edited again with the error on top of it
+func (p *Prompts) readFromEditor(prompt EditorPrompter) ([]string, error) {
+	content := prompt.InitialContent()
+
+	if last, ok := p.lastStep(); ok && last.id == p.current && last.err != nil {
+		content = editorErrorPrefix + last.err.Error() + "\n" + strings.Join(last.inputs, "\n")
+	}
+
+	file, err := os.CreateTemp("", "strumt-*.txt")
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return []string{}, err
+	}
+
+	if err := file.Close(); err != nil {
+		return []string{}, err
+	}
+
+	editor := p.editor()
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return []string{}, fmt.Errorf("can't run editor %s : %s", editor[0], err)
+	}
+
+	datas, err := os.ReadFile(file.Name())
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	inputs := []string{}
+
+	if len(datas) == 0 {
+		return inputs, nil
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(string(datas), "\n"), "\n") {
+		if strings.HasPrefix(line, editorErrorPrefix) {
+			continue
+		}
+
+		inputs = append(inputs, normalize(p.normalizers, line))
+	}
+
+	return inputs, nil
+}
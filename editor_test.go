@@ -0,0 +1,95 @@
+package strumt
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type IpsEditorPrompt struct {
+	IpsPrompt
+}
+
+func (r *IpsEditorPrompt) InitialContent() string {
+	return "127.0.0.1\n"
+}
+
+func createEditor(t *testing.T, answers ...string) (string, string) {
+	dir := t.TempDir()
+
+	for i, answer := range answers {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("answer%d", i+1)), []byte(answer), 0o644))
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+n=$(cat %[1]s/count 2>/dev/null || echo 0)
+n=$((n+1))
+echo $n > %[1]s/count
+cp "$1" %[1]s/seen$n
+cp %[1]s/answer$n "$1"
+`, dir)
+
+	editor := filepath.Join(dir, "editor")
+	assert.NoError(t, os.WriteFile(editor, []byte(script), 0o755))
+
+	return editor, dir
+}
+
+func TestPromptsRunWithEditor(t *testing.T) {
+	editor, dir := createEditor(t, "test\n1.2.3.4\n", "# error: test is not a valid IP\r\n1.2.3.4\r\n8.9.10.11\r\n")
+
+	var ips []string
+	var stdout bytes.Buffer
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString(""), &stdout)
+	p.SetEditor(editor)
+	p.AddMultilinePrompter(&IpsEditorPrompt{IpsPrompt{&ips, "Give some ips", "ips", "", "ips"}})
+	p.SetFirst("ips")
+	p.Run()
+
+	seen1, err := os.ReadFile(filepath.Join(dir, "seen1"))
+	assert.NoError(t, err)
+	seen2, err := os.ReadFile(filepath.Join(dir, "seen2"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"1.2.3.4", "8.9.10.11"}, ips)
+	assert.Equal(t, "127.0.0.1\n", string(seen1))
+	assert.Equal(t, "# error: test is not a valid IP\ntest\n1.2.3.4", string(seen2))
+	assert.Equal(t, "Give some ips\ntest is not a valid IP\n\nGive some ips\n", stdout.String())
+	assert.Len(t, p.Scenario(), 2)
+	assert.Equal(t, []string{"test", "1.2.3.4"}, p.Scenario()[0].Inputs())
+}
+
+func TestPromptsRunWithEditorFromEnvironment(t *testing.T) {
+	editor, _ := createEditor(t, "1.2.3.4\n")
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", editor)
+
+	var ips []string
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString(""), ioutil.Discard)
+	p.AddMultilinePrompter(&IpsEditorPrompt{IpsPrompt{&ips, "Give some ips", "ips", "", "ips"}})
+	p.SetFirst("ips")
+	p.Run()
+
+	assert.Equal(t, []string{"1.2.3.4"}, ips)
+}
+
+func TestPromptsRunWithFailingEditor(t *testing.T) {
+	var ips []string
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString(""), ioutil.Discard)
+	p.SetEditor("false")
+	p.AddMultilinePrompter(&IpsEditorPrompt{IpsPrompt{&ips, "Give some ips", "ips", "", "ips"}})
+	p.SetFirst("ips")
+	p.Run()
+
+	assert.Len(t, p.Scenario(), 1)
+	assert.EqualError(t, p.Scenario()[0].Error(), "can't run editor false : exit status 1")
+}
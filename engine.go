@@ -0,0 +1,232 @@
+package strumt
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// State represents where a prompt sequence stands, it gives
+// the prompter waiting for inputs or tells the sequence is done
+type State struct {
+	id       string
+	prompter Prompter
+	err      error
+	help     string
+}
+
+// ID returns the ID of the prompter waiting for inputs
+func (s State) ID() string {
+	return s.id
+}
+
+// Prompter returns the prompter waiting for inputs,
+// nil when the prompt sequence is done
+func (s State) Prompter() Prompter {
+	return s.prompter
+}
+
+// Error returns the error of the previous attempt of the
+// prompter waiting for inputs, or the error which ended
+// the prompt sequence when it's done
+func (s State) Error() error {
+	return s.err
+}
+
+// Help returns the help text of the prompter waiting for inputs
+// when the user has just asked for it, an empty string otherwise
+func (s State) Help() string {
+	return s.help
+}
+
+// Done returns true when the prompt sequence has ended
+func (s State) Done() bool {
+	return s.prompter == nil
+}
+
+// Start resets the prompt sequence and returns the state of its
+// first prompter. Combined with Submit, it lets any transport
+// (a chat bot, a GUI, a message queue) drive the prompt sequence
+// without using a reader and a writer
+func (p *Prompts) Start() State {
+	p.scenario = []Step{}
+	p.reviewFrom = 0
+	p.current = p.entry(p.first)
+	p.since = p.now()
+	p.resetGroups()
+	p.session.answers = map[string]Answer{}
+
+	for _, prompt := range p.prompts {
+		if s, ok := prompt.(SessionPrompter); ok {
+			s.SetSession(p.session)
+		}
+	}
+
+	return p.state()
+}
+
+// Submit gives inputs to the prompter waiting for them, a LinePrompter
+// only uses the first input. It parses inputs, records the step in the
+// scenario and returns the state of the next prompter to run. When the
+// input is the help trigger and the prompter implements Helper, the help
+// request is recorded and the prompter keeps on waiting for inputs.
+// Inputs are normalized as if they were read from the reader
+func (p *Prompts) Submit(inputs []string) State {
+	normalized := make([]string, len(inputs))
+
+	for i, input := range inputs {
+		normalized[i] = normalize(p.normalizers, input)
+	}
+
+	return p.submit(normalized, SourceUser)
+}
+
+// Abort ends the prompt sequence driven with Submit, e.g. when
+// the user leaves, the last step records err and is marked as
+// aborted. Nothing happens when the sequence is not running
+func (p *Prompts) Abort(err error) {
+	if _, ok := p.prompts[p.current]; ok {
+		p.abort([]string{}, err)
+	}
+}
+
+func (p *Prompts) submit(inputs []string, source Source) State {
+	prompt, ok := p.prompts[p.current]
+
+	if !ok {
+		return p.state()
+	}
+
+	if _, ok := prompt.(LinePrompter); ok {
+		if len(inputs) == 0 {
+			inputs = []string{""}
+		}
+
+		inputs = inputs[:1]
+
+		if d, ok := prompt.(DefaultPrompter); ok && source == SourceUser && inputs[0] == "" && d.Default() != "" {
+			inputs = []string{d.Default()}
+			source = SourceDefault
+		}
+	}
+
+	if p.helpRequested(prompt, inputs, source) {
+		return p.help(inputs, source)
+	}
+
+	step := Step{id: p.current, prompt: prompt.PromptString(), inputs: inputs, start: p.since, source: source, attempt: p.attempt()}
+
+	next, err := p.parse(inputs)
+
+	step.err = err
+	step.end = p.now()
+	step.target = next
+
+	if _, ok := p.prompts[next]; ok {
+		step.next = next
+	}
+
+	p.scenario = append(p.scenario, step)
+	p.current = step.next
+	p.since = step.end
+	p.logStep(step)
+
+	return p.state()
+}
+
+// helpRequested returns true when inputs given by
+// the user ask for the help of prompt
+func (p *Prompts) helpRequested(prompt Prompter, inputs []string, source Source) bool {
+	h, ok := prompt.(Helper)
+
+	return ok && h.Help() != "" && source == SourceUser && p.helpTrigger != "" && len(inputs) == 1 && inputs[0] == p.helpTrigger
+}
+
+// help records the user asked for the help of the current prompter,
+// the prompter keeps on waiting for inputs
+func (p *Prompts) help(inputs []string, source Source) State {
+	step := Step{
+		id:      p.current,
+		prompt:  p.prompts[p.current].PromptString(),
+		inputs:  inputs,
+		start:   p.since,
+		end:     p.now(),
+		next:    p.current,
+		target:  p.current,
+		source:  source,
+		attempt: p.attempt(),
+		help:    true,
+	}
+
+	p.scenario = append(p.scenario, step)
+	p.since = step.end
+	p.logStep(step)
+
+	return p.state()
+}
+
+// abort ends the prompt sequence because inputs
+// of the current prompter can't be read
+func (p *Prompts) abort(inputs []string, err error) {
+	// no prompter is running when the review is aborted
+	prompt := ""
+
+	if prompter, ok := p.prompts[p.current]; ok {
+		prompt = prompter.PromptString()
+	}
+
+	p.scenario = append(p.scenario, Step{
+		id:      p.current,
+		prompt:  prompt,
+		inputs:  inputs,
+		err:     err,
+		start:   p.since,
+		end:     p.now(),
+		source:  SourceUser,
+		attempt: p.attempt(),
+		aborted: true,
+	})
+	p.current = ""
+	p.logStep(p.scenario[len(p.scenario)-1])
+}
+
+// state returns the state of the current prompter, when the prompter
+// takes its inputs from the environment, they are submitted right away
+func (p *Prompts) state() State {
+	prompt, ok := p.prompts[p.current]
+
+	if !ok {
+		p.current = ""
+		state := State{}
+
+		if last, ok := p.lastStep(); ok {
+			state.err = p.redact(last).err
+		}
+
+		return state
+	}
+
+	if e, ok := prompt.(EnvPrompter); ok && !p.visited(p.current) {
+		if value, ok := os.LookupEnv(e.Env()); ok {
+			if _, ok := prompt.(LinePrompter); ok {
+				return p.submit([]string{value}, SourceEnv)
+			}
+
+			return p.submit(strings.Split(value, "\n"), SourceEnv)
+		}
+	}
+
+	state := State{id: p.current, prompter: prompt}
+
+	p.log(slog.LevelDebug, "prompt shown", slog.String("id", p.current), slog.Int("attempt", p.attempt()))
+
+	if last, ok := p.lastStep(); ok && last.id == p.current && last.err != nil {
+		state.err = p.redact(last).err
+	}
+
+	if h, ok := prompt.(Helper); ok && len(p.scenario) > 0 && p.scenario[len(p.scenario)-1].help {
+		state.help = h.Help()
+	}
+
+	return state
+}
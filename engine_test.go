@@ -0,0 +1,91 @@
+package strumt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromptsStartAndSubmit(t *testing.T) {
+	var username string
+	var port int
+	var ips []string
+
+	p := NewPromptsFromReaderAndWriter(nil, nil)
+	p.AddLinePrompter(&StringPrompt{&username, "Give a username", "username", "port", "username"})
+	p.AddLinePrompter(&IntPrompt{&port, "Give a port", "port", "ips", "port"})
+	p.AddMultilinePrompter(&IpsPrompt{&ips, "Give some ips", "ips", "", "ips"})
+	p.SetFirst("username")
+
+	state := p.Start()
+	assert.Equal(t, "username", state.ID())
+	assert.Equal(t, "Give a username", state.Prompter().PromptString())
+	assert.NoError(t, state.Error())
+	assert.False(t, state.Done())
+	assert.Equal(t, "username", p.Current())
+
+	state = p.Submit([]string{})
+	assert.Equal(t, "username", state.ID())
+	assert.EqualError(t, state.Error(), "Empty value given")
+
+	state = p.Submit([]string{"user", "ignored"})
+	assert.Equal(t, "port", state.ID())
+	assert.NoError(t, state.Error())
+
+	state = p.Submit([]string{"10000"})
+	assert.Equal(t, "ips", state.ID())
+
+	state = p.Submit([]string{"127.0.0.1", "test"})
+	assert.Equal(t, "ips", state.ID())
+	assert.EqualError(t, state.Error(), "test is not a valid IP")
+
+	state = p.Submit([]string{"127.0.0.1", "1.2.3.4"})
+	assert.True(t, state.Done())
+	assert.Nil(t, state.Prompter())
+	assert.NoError(t, state.Error())
+	assert.Equal(t, "", p.Current())
+
+	state = p.Submit([]string{"whatever"})
+	assert.True(t, state.Done())
+
+	assert.Equal(t, "user", username)
+	assert.Equal(t, 10000, port)
+	assert.Equal(t, []string{"127.0.0.1", "1.2.3.4"}, ips)
+	assert.Len(t, p.Scenario(), 5)
+	assert.Equal(t, []string{"user"}, p.Scenario()[1].Inputs())
+}
+
+func TestPromptsStartWithUnknownFirstPrompter(t *testing.T) {
+	p := NewPromptsFromReaderAndWriter(nil, nil)
+	p.SetFirst("whatever")
+
+	state := p.Start()
+
+	assert.True(t, state.Done())
+	assert.NoError(t, state.Error())
+}
+
+func TestPromptsSubmitEndingOnError(t *testing.T) {
+	p := NewPromptsFromReaderAndWriter(nil, nil)
+	p.AddLinePrompter(&StringPrompt{new(string), "Give a username", "username", "", ""})
+	p.SetFirst("username")
+	p.Start()
+
+	state := p.Submit([]string{""})
+
+	assert.True(t, state.Done())
+	assert.EqualError(t, state.Error(), "Empty value given")
+}
+
+func TestPromptsSubmitToUnregisteredPrompter(t *testing.T) {
+	p := NewPromptsFromReaderAndWriter(nil, nil)
+	p.AddLinePrompter(&StringPrompt{new(string), "Give a username", "username", "whatever", "username"})
+	p.SetFirst("username")
+	p.Start()
+
+	state := p.Submit([]string{"user"})
+
+	assert.True(t, state.Done())
+	assert.Equal(t, "", p.Scenario()[0].Next())
+	assert.Equal(t, "whatever", p.Scenario()[0].Target())
+}
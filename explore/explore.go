@@ -0,0 +1,220 @@
+// Package explore walks every path of a prompt sequence by submitting
+// candidate inputs to each prompter, it reports paths reaching the end of
+// the sequence, loops, panics, dead ends and prompters no candidate gets
+// past. Fuzz provides a ready fuzz target for go test -fuzz.
+//
+// Prompters often store parsed values, so each explored path is
+// replayed on a fresh prompt sequence built by a Factory.
+package explore
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+
+	"github.com/antham/strumt/v2"
+)
+
+// DefaultMaxDepth is the maximum number of steps of an explored path
+const DefaultMaxDepth = 50
+
+// LineCandidates are the inputs given to a LinePrompter
+// when no candidate has been defined for it
+var LineCandidates = []string{"", "y", "n", "0", "1", "-1", "42", "abc", "127.0.0.1"}
+
+// MultilineCandidates are the inputs given to a MultilinePrompter
+// when no candidate has been defined for it
+var MultilineCandidates = [][]string{{}, {"abc"}, {"1", "2"}, {"127.0.0.1"}}
+
+// Factory builds a fresh prompt sequence,
+// most of the time using strumt.NewPromptsFromReaderAndWriter
+type Factory func(io.Reader, io.Writer) strumt.Prompts
+
+// Move is an input submitted to a prompter
+type Move struct {
+	ID     string
+	Inputs []string
+}
+
+// Path is a sequence of moves
+type Path []Move
+
+// String returns a readable representation of the path
+func (p Path) String() string {
+	moves := []string{}
+
+	for _, m := range p {
+		moves = append(moves, fmt.Sprintf("%s:%q", m.ID, strings.Join(m.Inputs, "\n")))
+	}
+
+	return strings.Join(moves, " -> ")
+}
+
+// Panic records a prompter panicking, the last move of the path triggered it
+type Panic struct {
+	Path  Path
+	Value interface{}
+	Stack []byte
+}
+
+// DeadEnd records a transition to a prompter which is not registered
+type DeadEnd struct {
+	Path   Path
+	Target string
+}
+
+// Report gives the result of an exploration. Loops are paths coming back to a
+// prompter already passed, Stuck are paths leading to a prompter rejecting every
+// candidate and Truncated are paths stopped because they reached the maximum depth
+type Report struct {
+	Paths     []Path
+	Loops     []Path
+	Panics    []Panic
+	DeadEnds  []DeadEnd
+	Stuck     []Path
+	Truncated []Path
+}
+
+// Ok returns true when no panic and no dead end have been found
+func (r Report) Ok() bool {
+	return len(r.Panics) == 0 && len(r.DeadEnds) == 0
+}
+
+// Explorer walks paths of a prompt sequence
+type Explorer struct {
+	factory    Factory
+	candidates map[string][][]string
+	maxDepth   int
+}
+
+// New creates an explorer of prompt sequences built by factory
+func New(factory Factory) *Explorer {
+	return &Explorer{factory: factory, candidates: map[string][][]string{}, maxDepth: DefaultMaxDepth}
+}
+
+// SetCandidates defines inputs submitted to the prompter with the given ID
+// instead of the generated ones, a LinePrompter only uses the first input
+func (e *Explorer) SetCandidates(id string, candidates ...[]string) {
+	e.candidates[id] = candidates
+}
+
+// SetMaxDepth defines the maximum number of steps of an explored path
+func (e *Explorer) SetMaxDepth(depth int) {
+	e.maxDepth = depth
+}
+
+// Explore walks every path of the prompt sequence. From each prompter,
+// only the first candidate leading to a given prompter is followed, so a
+// path is a distinct sequence of transitions rather than of inputs
+func (e *Explorer) Explore() Report {
+	r := Report{}
+	p := e.factory(nil, io.Discard)
+	state := p.Start()
+
+	if state.Done() {
+		r.Paths = append(r.Paths, Path{})
+		return r
+	}
+
+	e.walk(&r, Path{}, map[string]bool{state.ID(): true}, state)
+
+	return r
+}
+
+func (e *Explorer) walk(r *Report, path Path, visited map[string]bool, state strumt.State) {
+	if len(path) >= e.maxDepth {
+		r.Truncated = append(r.Truncated, path)
+		return
+	}
+
+	followed := map[string]bool{}
+
+	for _, inputs := range e.candidatesOf(state.ID(), state.Prompter()) {
+		next := append(append(Path{}, path...), Move{state.ID(), inputs})
+		steps, nextState, recovered := e.replay(next)
+
+		if recovered != nil {
+			r.Panics = append(r.Panics, *recovered)
+			continue
+		}
+
+		// a prompter asking again for its inputs doesn't
+		// lead anywhere, other candidates are tried instead
+		if steps[0].Error() != nil && steps[0].Next() == state.ID() {
+			continue
+		}
+
+		// steps recorded after the first one are inputs taken from the environment
+		last := steps[len(steps)-1]
+
+		if followed[last.Target()] {
+			continue
+		}
+
+		followed[last.Target()] = true
+
+		switch {
+		case last.Target() != "" && last.Next() == "":
+			r.DeadEnds = append(r.DeadEnds, DeadEnd{next, last.Target()})
+		case nextState.Done():
+			r.Paths = append(r.Paths, next)
+		case visited[nextState.ID()]:
+			r.Loops = append(r.Loops, next)
+		default:
+			visited[nextState.ID()] = true
+			e.walk(r, next, visited, nextState)
+			delete(visited, nextState.ID())
+		}
+	}
+
+	if len(followed) == 0 {
+		r.Stuck = append(r.Stuck, path)
+	}
+}
+
+func (e *Explorer) candidatesOf(id string, prompt strumt.Prompter) [][]string {
+	if candidates, ok := e.candidates[id]; ok {
+		return candidates
+	}
+
+	if _, ok := prompt.(strumt.MultilinePrompter); ok {
+		return MultilineCandidates
+	}
+
+	candidates := [][]string{}
+
+	if c, ok := prompt.(strumt.ChoicePrompter); ok {
+		for _, choice := range c.Choices() {
+			candidates = append(candidates, []string{choice})
+		}
+	}
+
+	for _, candidate := range LineCandidates {
+		candidates = append(candidates, []string{candidate})
+	}
+
+	return candidates
+}
+
+// replay submits moves of path to a fresh prompt sequence and returns
+// steps recorded by the last move and the state of the sequence afterward
+func (e *Explorer) replay(path Path) (steps []strumt.Step, state strumt.State, recovered *Panic) {
+	defer func() {
+		if v := recover(); v != nil {
+			recovered = &Panic{path, v, debug.Stack()}
+		}
+	}()
+
+	p := e.factory(nil, io.Discard)
+	p.Start()
+
+	from := 0
+
+	for _, move := range path {
+		from = len(p.Scenario())
+		state = p.Submit(move.Inputs)
+	}
+
+	return p.Scenario()[from:], state, nil
+}
@@ -0,0 +1,224 @@
+package explore
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/antham/strumt/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type NamePrompt struct{}
+
+func (n *NamePrompt) ID() string {
+	return "name"
+}
+
+func (n *NamePrompt) PromptString() string {
+	return "Enter your name"
+}
+
+func (n *NamePrompt) Parse(value string) error {
+	if value == "" {
+		return fmt.Errorf("a name is required")
+	}
+
+	return nil
+}
+
+func (n *NamePrompt) NextOnSuccess(value string) string {
+	return "age"
+}
+
+func (n *NamePrompt) NextOnError(err error) string {
+	return "name"
+}
+
+type AgePrompt struct {
+	age int
+}
+
+func (a *AgePrompt) ID() string {
+	return "age"
+}
+
+func (a *AgePrompt) PromptString() string {
+	return "Enter your age"
+}
+
+func (a *AgePrompt) Parse(value string) error {
+	age, err := strconv.Atoi(value)
+
+	if err != nil {
+		return fmt.Errorf("%s is not a valid number", value)
+	}
+
+	a.age = age
+
+	return nil
+}
+
+func (a *AgePrompt) NextOnSuccess(value string) string {
+	if a.age < 18 {
+		return "guardian"
+	}
+
+	return "shell"
+}
+
+func (a *AgePrompt) NextOnError(err error) string {
+	return "age"
+}
+
+type ShellPrompt struct {
+	broken bool
+}
+
+func (s *ShellPrompt) ID() string {
+	return "shell"
+}
+
+func (s *ShellPrompt) PromptString() string {
+	return "Choose a shell"
+}
+
+func (s *ShellPrompt) Parse(value string) error {
+	for _, choice := range s.Choices() {
+		if value == choice {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is not a supported shell", value)
+}
+
+func (s *ShellPrompt) NextOnSuccess(value string) string {
+	switch value {
+	case "zsh":
+		return "hosts"
+	case "fish":
+		if s.broken {
+			panic("fish is not implemented")
+		}
+	}
+
+	return ""
+}
+
+func (s *ShellPrompt) NextOnError(err error) string {
+	return "shell"
+}
+
+func (s *ShellPrompt) Choices() []string {
+	return []string{"bash", "zsh", "fish"}
+}
+
+type HostsPrompt struct{}
+
+func (h *HostsPrompt) ID() string {
+	return "hosts"
+}
+
+func (h *HostsPrompt) PromptString() string {
+	return "Give some hosts"
+}
+
+func (h *HostsPrompt) Parse(values []string) error {
+	return nil
+}
+
+func (h *HostsPrompt) NextOnSuccess(values []string) string {
+	if len(values) > 0 && values[0] == "again" {
+		return "name"
+	}
+
+	return ""
+}
+
+func (h *HostsPrompt) NextOnError(err error) string {
+	return "hosts"
+}
+
+type GuardianPrompt struct {
+	NamePrompt
+}
+
+func (g *GuardianPrompt) ID() string {
+	return "guardian"
+}
+
+func (g *GuardianPrompt) NextOnSuccess(value string) string {
+	return ""
+}
+
+func (g *GuardianPrompt) NextOnError(err error) string {
+	return "guardian"
+}
+
+func newFactory(broken bool) Factory {
+	return func(reader io.Reader, writer io.Writer) strumt.Prompts {
+		p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+		p.AddLinePrompter(&NamePrompt{})
+		p.AddLinePrompter(&AgePrompt{})
+		p.AddLinePrompter(&ShellPrompt{broken})
+		p.AddMultilinePrompter(&HostsPrompt{})
+
+		if !broken {
+			p.AddLinePrompter(&GuardianPrompt{})
+		}
+
+		p.SetFirst("name")
+
+		return p
+	}
+}
+
+func TestExplore(t *testing.T) {
+	e := New(newFactory(true))
+	e.SetCandidates("hosts", []string{"h1"}, []string{"again"})
+
+	r := e.Explore()
+
+	name := Move{"name", []string{"y"}}
+	age := Move{"age", []string{"42"}}
+	zsh := Move{"shell", []string{"zsh"}}
+
+	assert.Equal(t, []Path{
+		{name, age, {"shell", []string{"bash"}}},
+		{name, age, zsh, {"hosts", []string{"h1"}}},
+	}, r.Paths)
+	assert.Equal(t, []Path{{name, age, zsh, {"hosts", []string{"again"}}}}, r.Loops)
+	assert.Equal(t, []DeadEnd{{Path{name, {"age", []string{"0"}}}, "guardian"}}, r.DeadEnds)
+	assert.Len(t, r.Panics, 1)
+	assert.Equal(t, Path{name, age, {"shell", []string{"fish"}}}, r.Panics[0].Path)
+	assert.Equal(t, "fish is not implemented", r.Panics[0].Value)
+	assert.Empty(t, r.Stuck)
+	assert.Empty(t, r.Truncated)
+	assert.False(t, r.Ok())
+	assert.Equal(t, `name:"y" -> age:"0"`, r.DeadEnds[0].Path.String())
+}
+
+func TestExploreStuckAndTruncatedPaths(t *testing.T) {
+	e := New(newFactory(false))
+	e.SetCandidates("age", []string{"old"})
+	e.SetMaxDepth(1)
+
+	r := e.Explore()
+
+	assert.Empty(t, r.Paths)
+	assert.Equal(t, []Path{{{"name", []string{"y"}}}}, r.Truncated)
+	assert.True(t, r.Ok())
+
+	e.SetMaxDepth(DefaultMaxDepth)
+
+	r = e.Explore()
+
+	assert.Equal(t, []Path{{{"name", []string{"y"}}}}, r.Stuck)
+	assert.True(t, r.Ok())
+}
+
+func FuzzFlow(f *testing.F) {
+	Fuzz(f, newFactory(false))
+}
@@ -0,0 +1,79 @@
+package explore
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/antham/strumt/v2"
+)
+
+// Fuzz runs the prompt sequence built by factory with inputs generated by the
+// fuzzing engine, it fails when a prompter panics or when a transition leads to
+// a prompter which is not registered. Each line of the fuzzed data is an input,
+// an empty line ends the inputs of a MultilinePrompter. Paths found by an
+// Explorer are added to the seed corpus :
+//
+//	func FuzzFlow(f *testing.F) {
+//		explore.Fuzz(f, newPrompts)
+//	}
+func Fuzz(f *testing.F, factory Factory) {
+	f.Add(strings.Join(LineCandidates, "\n"))
+
+	for _, path := range New(factory).Explore().Paths {
+		f.Add(encode(factory, path))
+	}
+
+	f.Fuzz(func(t *testing.T, data string) {
+		p := factory(nil, io.Discard)
+		state := p.Start()
+		lines := strings.Split(data, "\n")
+
+		for len(lines) > 0 && !state.Done() {
+			var inputs []string
+
+			inputs, lines = next(state.Prompter(), lines)
+			state = p.Submit(inputs)
+		}
+
+		for _, step := range p.Scenario() {
+			if step.Target() != "" && step.Next() == "" {
+				t.Fatalf("prompter %s leads to %s which is not registered", step.ID(), step.Target())
+			}
+		}
+	})
+}
+
+// next splits the inputs of the given prompter from the remaining lines
+func next(prompt strumt.Prompter, lines []string) ([]string, []string) {
+	if _, ok := prompt.(strumt.MultilinePrompter); !ok {
+		return lines[:1], lines[1:]
+	}
+
+	for i, line := range lines {
+		if line == "" {
+			return lines[:i], lines[i+1:]
+		}
+	}
+
+	return lines, []string{}
+}
+
+// encode turns a path into fuzzed data
+func encode(factory Factory, path Path) string {
+	p := factory(nil, io.Discard)
+	lines := []string{}
+
+	for _, move := range path {
+		prompt, _ := p.Prompter(move.ID)
+
+		if _, ok := prompt.(strumt.MultilinePrompter); ok {
+			lines = append(append(lines, move.Inputs...), "")
+			continue
+		}
+
+		lines = append(lines, move.Inputs...)
+	}
+
+	return strings.Join(lines, "\n")
+}
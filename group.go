@@ -0,0 +1,219 @@
+package strumt
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	groupMoreSuffix  = "#more"
+	groupStartSuffix = "#start"
+)
+
+// Record holds inputs accepted during one iteration
+// of a group, keyed by prompter ID
+type Record map[string][]string
+
+// Group runs a prompt sequence several times to collect
+// a list of records, after each iteration the user is asked
+// whether another record has to be added
+type Group struct {
+	flow              *Prompts
+	min               int
+	max               int
+	promptString      string
+	startPromptString string
+	onRecord          func(Record)
+	records           []Record
+}
+
+// NewGroup creates a group running flow at least min times
+// and at most max times, a max of 0 means there is no limit.
+// When min is 0, the user is asked whether a first record has
+// to be added. It panics if min or max are negative or if min
+// is greater than max
+func NewGroup(flow *Prompts, min int, max int) *Group {
+	if min < 0 || max < 0 || (max > 0 && min > max) {
+		panic(fmt.Sprintf("strumt: invalid group bounds, min %d and max %d", min, max))
+	}
+
+	return &Group{flow: flow, min: min, max: max, promptString: "Add another ? [y/N]", startPromptString: "Add one ? [y/N]"}
+}
+
+// SetPromptString defines the question asked
+// to know if another record has to be added
+func (g *Group) SetPromptString(prompt string) {
+	g.promptString = prompt
+}
+
+// SetStartPromptString defines the question asked to know
+// if a first record has to be added, when min is 0
+func (g *Group) SetStartPromptString(prompt string) {
+	g.startPromptString = prompt
+}
+
+// OnRecord defines a function called each time an iteration ends,
+// useful to copy values stored by prompters of the group
+// before they are overwritten by the next iteration
+func (g *Group) OnRecord(f func(Record)) {
+	g.onRecord = f
+}
+
+// Records retrieves a record per iteration
+func (g *Group) Records() []Record {
+	return g.records
+}
+
+// AddGroup adds group as a single prompter referenced by id,
+// prompters of the group are registered like subflow
+// prompters. When no more record has to be added, the prompt
+// sequence goes on with the prompter referenced by next
+func (p *Prompts) AddGroup(id string, group *Group, next string) {
+	first := id + "." + group.flow.first
+	more := &morePrompt{group: group, id: id, first: first, next: next, current: Record{}}
+
+	p.AddSubflow(id, group.flow, id+groupMoreSuffix)
+	p.prompts[id+groupMoreSuffix] = more
+	p.groups[id+groupMoreSuffix] = more
+
+	if group.min == 0 {
+		p.prompts[id+groupStartSuffix] = &startPrompt{group: group, id: id, first: first, next: next}
+		p.entries[id] = id + groupStartSuffix
+	}
+}
+
+// collect records inputs accepted by a prompter of a group
+func (p *Prompts) collect(key string, inputs []string) {
+	for moreKey, more := range p.groups {
+		prefix := strings.TrimSuffix(moreKey, groupMoreSuffix) + "."
+
+		if strings.HasPrefix(key, prefix) {
+			more.current[strings.TrimPrefix(key, prefix)] = inputs
+		}
+	}
+}
+
+func (p *Prompts) resetGroups() {
+	for _, more := range p.groups {
+		more.group.records = nil
+		more.current = Record{}
+	}
+}
+
+// morePrompt is the prompter asking whether
+// another iteration of a group has to be run
+type morePrompt struct {
+	group   *Group
+	id      string
+	first   string
+	next    string
+	current Record
+	more    bool
+}
+
+// end records the current iteration and returns whether the user
+// has to be asked for another one, otherwise it returns the ID
+// to go to, from the morePrompt point of view
+func (m *morePrompt) end() (string, bool) {
+	m.group.records = append(m.group.records, m.current)
+
+	if m.group.onRecord != nil {
+		m.group.onRecord(m.current)
+	}
+
+	m.current = Record{}
+
+	switch {
+	case len(m.group.records) < m.group.min:
+		return m.first, false
+	case m.group.max > 0 && len(m.group.records) >= m.group.max:
+		return m.next, false
+	}
+
+	return "", true
+}
+
+func (m *morePrompt) ID() string {
+	return m.id + groupMoreSuffix
+}
+
+func (m *morePrompt) PromptString() string {
+	return m.group.promptString
+}
+
+func (m *morePrompt) Parse(value string) error {
+	more, err := parseYesNo(value)
+	m.more = more
+
+	return err
+}
+
+func (m *morePrompt) NextOnSuccess(value string) string {
+	if m.more {
+		return m.first
+	}
+
+	return m.next
+}
+
+func (m *morePrompt) NextOnError(err error) string {
+	return m.ID()
+}
+
+func (m *morePrompt) Transitions() []string {
+	return []string{m.first, m.next}
+}
+
+// startPrompt is the prompter asking whether the first
+// iteration of a group without minimum has to be run
+type startPrompt struct {
+	group *Group
+	id    string
+	first string
+	next  string
+	start bool
+}
+
+func (s *startPrompt) ID() string {
+	return s.id + groupStartSuffix
+}
+
+func (s *startPrompt) PromptString() string {
+	return s.group.startPromptString
+}
+
+func (s *startPrompt) Parse(value string) error {
+	start, err := parseYesNo(value)
+	s.start = start
+
+	return err
+}
+
+func (s *startPrompt) NextOnSuccess(value string) string {
+	if s.start {
+		return s.first
+	}
+
+	return s.next
+}
+
+func (s *startPrompt) NextOnError(err error) string {
+	return s.ID()
+}
+
+func (s *startPrompt) Transitions() []string {
+	return []string{s.first, s.next}
+}
+
+// parseYesNo parses the answer of a yes or no
+// question, an empty answer means no
+func parseYesNo(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "y", "yes":
+		return true, nil
+	case "", "n", "no":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("You must answer yes or no")
+}
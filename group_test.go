@@ -0,0 +1,145 @@
+package strumt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromptsRunWithGroup(t *testing.T) {
+	type scenario struct {
+		name        string
+		min         int
+		max         int
+		input       string
+		connections []Connection
+		records     []Record
+	}
+
+	scenarios := []scenario{
+		{
+			"No record",
+			0,
+			0,
+			"\nuser\n",
+			[]Connection{},
+			nil,
+		},
+		{
+			"Single iteration",
+			0,
+			0,
+			"y\ndb1\n5432\n\nuser\n",
+			[]Connection{{"db1", 5432}},
+			[]Record{{"host": {"db1"}, "port": {"5432"}}},
+		},
+		{
+			"Several iterations",
+			0,
+			0,
+			"yes\ndb1\n5432\ny\ndb2\n5433\nwhatever\nyes\ndb3\n5434\nno\nuser\n",
+			[]Connection{{"db1", 5432}, {"db2", 5433}, {"db3", 5434}},
+			[]Record{{"host": {"db1"}, "port": {"5432"}}, {"host": {"db2"}, "port": {"5433"}}, {"host": {"db3"}, "port": {"5434"}}},
+		},
+		{
+			"Minimum number of iterations",
+			2,
+			0,
+			"db1\n5432\ndb2\n5433\nn\nuser\n",
+			[]Connection{{"db1", 5432}, {"db2", 5433}},
+			[]Record{{"host": {"db1"}, "port": {"5432"}}, {"host": {"db2"}, "port": {"5433"}}},
+		},
+		{
+			"Maximum number of iterations",
+			0,
+			2,
+			"y\ndb1\n5432\ny\ndb2\n5433\nuser\n",
+			[]Connection{{"db1", 5432}, {"db2", 5433}},
+			[]Record{{"host": {"db1"}, "port": {"5432"}}, {"host": {"db2"}, "port": {"5433"}}},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var username string
+			connection := Connection{}
+			connections := []Connection{}
+
+			group := NewGroup(newConnectionFlow(&connection), s.min, s.max)
+			group.OnRecord(func(Record) {
+				connections = append(connections, connection)
+			})
+
+			p := NewPromptsFromReaderAndWriter(bytes.NewBufferString(s.input), ioutil.Discard)
+			p.AddGroup("databases", group, "username")
+			p.AddLinePrompter(&StringPrompt{&username, "Give a username", "username", "", "username"})
+			p.SetFirst("databases")
+			p.Run()
+
+			assert.Equal(t, s.connections, connections)
+			assert.Equal(t, s.records, group.Records())
+			assert.Equal(t, "user", username)
+		})
+	}
+}
+
+func TestPromptsRunWithGroupInSubflow(t *testing.T) {
+	connection := Connection{}
+
+	group := NewGroup(newConnectionFlow(&connection), 0, 0)
+	group.SetPromptString("Another database ?")
+	group.SetStartPromptString("Add a database ?")
+
+	flow := NewPromptsFromReaderAndWriter(nil, nil)
+	flow.AddGroup("databases", group, "")
+	flow.SetFirst("databases")
+
+	var stdout bytes.Buffer
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("y\ndb1\n5432\ny\ndb2\n5433\nn\n"), &stdout)
+	p.AddSubflow("cluster", &flow, "")
+	p.SetFirst("cluster")
+	p.Run()
+
+	assert.Equal(t, []Record{{"host": {"db1"}, "port": {"5432"}}, {"host": {"db2"}, "port": {"5433"}}}, group.Records())
+	assert.Equal(t, "Add a database ?\n\nGive a host\n\nGive a port\n\nAnother database ?\n\nGive a host\n\nGive a port\n\nAnother database ?\n", stdout.String())
+}
+
+func TestPromptsRunEndingOnErrorInGroup(t *testing.T) {
+	connection := Connection{}
+
+	flow := NewPromptsFromReaderAndWriter(nil, nil)
+	flow.AddLinePrompter(&StringPrompt{&connection.Host, "Give a host", "host", "port", "host"})
+	flow.AddLinePrompter(&IntPrompt{&connection.Port, "Give a port", "port", "", ""})
+	flow.SetFirst("host")
+
+	group := NewGroup(&flow, 1, 0)
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("db1\nwhatever\n"), ioutil.Discard)
+	p.AddGroup("databases", group, "")
+	p.SetFirst("databases")
+	p.Run()
+
+	assert.Empty(t, group.Records())
+	assert.Len(t, p.Scenario(), 2)
+	assert.Error(t, p.Scenario()[1].Error())
+	assert.Empty(t, p.Scenario()[1].Next())
+}
+
+func TestNewGroupWithInvalidBounds(t *testing.T) {
+	type scenario struct {
+		min int
+		max int
+	}
+
+	scenarios := []scenario{{-1, 0}, {0, -1}, {3, 2}}
+
+	for _, s := range scenarios {
+		assert.Panics(t, func() { NewGroup(&Prompts{}, s.min, s.max) })
+	}
+
+	assert.NotPanics(t, func() { NewGroup(&Prompts{}, 2, 2) })
+	assert.NotPanics(t, func() { NewGroup(&Prompts{}, 2, 0) })
+}
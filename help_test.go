@@ -0,0 +1,195 @@
+package strumt
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type HelpPrompt struct {
+	StringPrompt
+	help string
+}
+
+func (h *HelpPrompt) Help() string {
+	return h.help
+}
+
+type HostsPrompt struct {
+	IpsPrompt
+}
+
+func (h *HostsPrompt) Help() string {
+	return "One IP per line, an empty line ends the list"
+}
+
+func newHelpFlow(reader io.Reader, writer io.Writer, username *string, help string) Prompts {
+	p := NewPromptsFromReaderAndWriter(reader, writer)
+	p.AddLinePrompter(&HelpPrompt{StringPrompt{username, "Give a username", "username", "", "username"}, help})
+	p.SetFirst("username")
+
+	return p
+}
+
+func TestPromptsRunWithHelp(t *testing.T) {
+	type scenario struct {
+		name     string
+		trigger  *string
+		help     string
+		input    string
+		output   string
+		username string
+		attempts []int
+		helps    []bool
+	}
+
+	trigger := "help"
+	disabled := ""
+
+	scenarios := []scenario{
+		{
+			"Help shown",
+			nil,
+			"The login of your account",
+			"?\nuser\n",
+			"Give a username\nThe login of your account\n\nGive a username\n",
+			"user",
+			[]int{1, 1},
+			[]bool{true, false},
+		},
+		{
+			"Help not counted as an attempt",
+			nil,
+			"The login of your account",
+			"\n?\n?\nuser\n",
+			"Give a username\nEmpty value given\n\nGive a username\nThe login of your account\n\nGive a username\nThe login of your account\n\nGive a username\n",
+			"user",
+			[]int{1, 2, 2, 2},
+			[]bool{false, true, true, false},
+		},
+		{
+			"Custom trigger",
+			&trigger,
+			"The login of your account",
+			"help\n?\n",
+			"Give a username\nThe login of your account\n\nGive a username\n",
+			"?",
+			[]int{1, 1},
+			[]bool{true, false},
+		},
+		{
+			"Help disabled",
+			&disabled,
+			"The login of your account",
+			"?\n",
+			"Give a username\n",
+			"?",
+			[]int{1},
+			[]bool{false},
+		},
+		{
+			"No help text",
+			nil,
+			"",
+			"?\n",
+			"Give a username\n",
+			"?",
+			[]int{1},
+			[]bool{false},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var username string
+			var output bytes.Buffer
+
+			p := newHelpFlow(bytes.NewBufferString(s.input), &output, &username, s.help)
+
+			if s.trigger != nil {
+				p.SetHelpTrigger(*s.trigger)
+			}
+
+			p.Run()
+
+			attempts := []int{}
+			helps := []bool{}
+
+			for _, step := range p.Scenario() {
+				attempts = append(attempts, step.Attempt())
+				helps = append(helps, step.HelpRequested())
+			}
+
+			assert.Equal(t, s.output, output.String())
+			assert.Equal(t, s.username, username)
+			assert.Equal(t, s.attempts, attempts)
+			assert.Equal(t, s.helps, helps)
+		})
+	}
+}
+
+func TestPromptsRunWithMultilineHelp(t *testing.T) {
+	var ips []string
+	var output bytes.Buffer
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("?\n\n127.0.0.1\n\n"), &output)
+	p.AddMultilinePrompter(&HostsPrompt{IpsPrompt{&ips, "Give some ips", "ips", "", "ips"}})
+	p.SetFirst("ips")
+	p.Run()
+
+	assert.Equal(t, "Give some ips\nOne IP per line, an empty line ends the list\n\nGive some ips\n", output.String())
+	assert.Equal(t, []string{"127.0.0.1"}, ips)
+}
+
+func TestPromptsSubmitHelp(t *testing.T) {
+	var username string
+
+	p := newHelpFlow(nil, nil, &username, "The login of your account")
+
+	state := p.Start()
+	assert.Equal(t, "", state.Help())
+
+	state = p.Submit([]string{""})
+	assert.EqualError(t, state.Error(), "Empty value given")
+
+	state = p.Submit([]string{"?"})
+	assert.Equal(t, "username", state.ID())
+	assert.Equal(t, "The login of your account", state.Help())
+	assert.EqualError(t, state.Error(), "Empty value given")
+
+	state = p.Submit([]string{"user"})
+	assert.True(t, state.Done())
+	assert.Equal(t, "", state.Help())
+	assert.Equal(t, "user", username)
+}
+
+func TestPromptsSaveAndResumeWithHelp(t *testing.T) {
+	var username string
+	var port int
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("?\nuser\n"), ioutil.Discard)
+	p.AddLinePrompter(&HelpPrompt{StringPrompt{&username, "Give a username", "username", "port", "username"}, "The login of your account"})
+	p.AddLinePrompter(&IntPrompt{&port, "Give a port", "port", "", "port"})
+	p.SetFirst("username")
+	p.Run()
+
+	state := bytes.Buffer{}
+	assert.NoError(t, p.Save(&state))
+
+	username = ""
+	p.reader.Reset(strings.NewReader("10000\n"))
+	p.SetHelpTrigger("")
+
+	assert.NoError(t, p.Resume(bytes.NewReader(state.Bytes())))
+
+	p.Run()
+
+	assert.Equal(t, "user", username)
+	assert.Equal(t, 10000, port)
+	assert.Len(t, p.Scenario(), 3)
+	assert.True(t, p.Scenario()[0].HelpRequested())
+}
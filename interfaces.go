@@ -46,6 +46,97 @@ type MultilinePrompter interface {
 	Parse([]string) error
 }
 
+// EditorPrompter defines a mutiline prompter
+// whose input is given through an external editor
+// instead of being typed line by line. The editor is
+// defined by the VISUAL or EDITOR environment variables
+// and uses the process standard input and output.
+//
+// InitialContent returns the content of the file
+// opened in the editor, when Parse fails the editor is
+// opened again with the previous content and the error
+type EditorPrompter interface {
+	MultilinePrompter
+	InitialContent() string
+}
+
+// DefaultPrompter can be implemented by a LinePrompter
+// to define the input used when the user gives an empty line
+type DefaultPrompter interface {
+	Default() string
+}
+
+// EnvPrompter can be implemented by a prompter to take
+// its input from an environment variable, Env returns the
+// variable name. When the variable is defined, the prompt
+// is not displayed and its value is given to Parse, a multiline
+// prompter gets one input per line, the step is recorded with
+// SourceEnv. The variable is only read the first time the prompter
+// is reached in a prompt sequence, afterwards or if Parse fails,
+// the user is prompted as usual
+type EnvPrompter interface {
+	Env() string
+}
+
+// ChoicePrompter can be implemented by a prompter
+// to expose the inputs it accepts, so they can be
+// displayed as choices by an user interface
+type ChoicePrompter interface {
+	Choices() []string
+}
+
+// ValuePrompter can be implemented by a prompter
+// to provide the value parsed from the inputs, Value
+// is called each time Parse succeeds and the result
+// is available with the prompter answer
+type ValuePrompter interface {
+	Value() interface{}
+}
+
+// TransitionPrompter can be implemented by a prompter to declare
+// the IDs NextOnSuccess can return, an empty string standing for
+// the end of the sequence. Declared transitions are used to compute
+// the progress of the prompt sequence
+type TransitionPrompter interface {
+	Transitions() []string
+}
+
+// Sensitive can be implemented by a prompter whose inputs
+// must not be disclosed (e.g. passwords or tokens). When Sensitive
+// returns true, inputs are replaced with Redacted in the scenario,
+// in saved prompt sequences and in rendered errors, Parse still
+// gets the real inputs
+type Sensitive interface {
+	Sensitive() bool
+}
+
+// Helper can be implemented by a prompter to provide a long-form
+// help text, it's shown when the user types the help trigger ("?"
+// by default) instead of giving it to Parse. A MultilinePrompter
+// shows help when the trigger is its only input. An empty help
+// text means the prompter has no help
+type Helper interface {
+	Help() string
+}
+
+// SessionPrompter can be implemented by a prompter to access
+// the session of the prompt sequence, SetSession is called
+// when the sequence starts. The session lets prompters
+// branch on inputs given to other prompters and share values
+type SessionPrompter interface {
+	SetSession(*Session)
+}
+
+// MultilineTerminator can be implemented by a MultilinePrompter
+// to define the line ending the input, this line is not part of
+// the inputs given to Parse. When this interface is not implemented,
+// the default behaviour is to end the input with an empty line,
+// so blank lines can't be part of the inputs. In both cases,
+// reaching the end of the input (e.g. Ctrl-D) ends the input as well
+type MultilineTerminator interface {
+	Terminator() string
+}
+
 // PromptRenderer can be implemented to customize
 // the way prompt is rendered, original PromptString is given
 // as second parameter
@@ -59,6 +150,12 @@ type ErrorRenderer interface {
 	PrintError(io.Writer, error)
 }
 
+// HelpRenderer can be implemented to customize
+// the way the help text of a Helper is rendered
+type HelpRenderer interface {
+	PrintHelp(io.Writer, string)
+}
+
 // SeparatorRenderer can be implemented to customize
 // the way a prompt is separated from another. When
 // this interface is not implemented, the default behaviour
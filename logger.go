@@ -0,0 +1,60 @@
+package strumt
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SetLogger defines the logger receiving structured records of the
+// prompt sequence : prompts shown, inputs accepted or rejected,
+// help requests, transitions and the end of the sequence. Inputs of sensitive
+// prompters are redacted. Nothing is logged by default
+func (p *Prompts) SetLogger(logger *slog.Logger) {
+	p.logger = logger
+}
+
+func (p *Prompts) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	if p.logger == nil {
+		return
+	}
+
+	p.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// logStep logs the outcome of a step recorded in the scenario
+func (p *Prompts) logStep(step Step) {
+	step = p.redact(step)
+	attrs := []slog.Attr{
+		slog.String("id", step.id),
+		slog.Int("attempt", step.attempt),
+		slog.String("source", string(step.source)),
+		slog.Any("inputs", step.inputs),
+		slog.Duration("duration", step.end.Sub(step.start)),
+	}
+
+	switch {
+	case step.help:
+		p.log(slog.LevelDebug, "help shown", attrs...)
+		return
+	case step.aborted:
+		p.log(slog.LevelWarn, "prompt sequence aborted", append(attrs, slog.String("error", step.err.Error()))...)
+		return
+	case step.err != nil:
+		p.log(slog.LevelWarn, "input rejected", append(attrs, slog.String("error", step.err.Error()))...)
+	default:
+		p.log(slog.LevelInfo, "input accepted", attrs...)
+	}
+
+	if step.next != "" {
+		p.log(slog.LevelDebug, "transition", slog.String("from", step.id), slog.String("to", step.next))
+		return
+	}
+
+	finished := []slog.Attr{slog.Int("steps", len(p.scenario))}
+
+	if step.err != nil {
+		finished = append(finished, slog.String("error", step.err.Error()))
+	}
+
+	p.log(slog.LevelInfo, "prompt sequence finished", finished...)
+}
@@ -0,0 +1,87 @@
+package strumt
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromptsRunWithLogger(t *testing.T) {
+	type scenario struct {
+		name   string
+		inputs string
+		logs   []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Sequence finished",
+			"user\nabc\ns3cr3t-pa55\n10000\n",
+			[]string{
+				`level=DEBUG msg="prompt shown" id=username attempt=1`,
+				`level=INFO msg="input accepted" id=username attempt=1 source=user inputs=[user]`,
+				`level=DEBUG msg=transition from=username to=password`,
+				`level=DEBUG msg="prompt shown" id=password attempt=1`,
+				`level=WARN msg="input rejected" id=password attempt=1 source=user inputs=[********] error="******** is too short"`,
+				`level=DEBUG msg=transition from=password to=password`,
+				`level=DEBUG msg="prompt shown" id=password attempt=2`,
+				`level=INFO msg="input accepted" id=password attempt=2 source=user inputs=[********]`,
+				`level=DEBUG msg=transition from=password to=port`,
+				`level=DEBUG msg="prompt shown" id=port attempt=1`,
+				`level=INFO msg="input accepted" id=port attempt=1 source=user inputs=[10000]`,
+				`level=INFO msg="prompt sequence finished" steps=4`,
+			},
+		},
+		{
+			"Sequence aborted",
+			"user\n",
+			[]string{
+				`level=DEBUG msg="prompt shown" id=username attempt=1`,
+				`level=INFO msg="input accepted" id=username attempt=1 source=user inputs=[user]`,
+				`level=DEBUG msg=transition from=username to=password`,
+				`level=DEBUG msg="prompt shown" id=password attempt=1`,
+				`level=WARN msg="prompt sequence aborted" id=password attempt=1 source=user inputs=[] error=EOF`,
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			logs := bytes.Buffer{}
+			logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{
+				Level: slog.LevelDebug,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey || a.Key == "duration" {
+						return slog.Attr{}
+					}
+
+					return a
+				},
+			}))
+
+			p := newRedactFlow(bytes.NewBufferString(s.inputs), &bytes.Buffer{}, new(string), new(int), true)
+			p.SetLogger(logger)
+			p.Run()
+
+			assert.Equal(t, s.logs, strings.Split(strings.TrimSpace(logs.String()), "\n"))
+		})
+	}
+}
+
+func TestPromptsResumeWithLogger(t *testing.T) {
+	p := newRedactFlow(bytes.NewBufferString("user\n"), &bytes.Buffer{}, new(string), new(int), false)
+	p.Run()
+
+	state := bytes.Buffer{}
+	assert.NoError(t, p.Save(&state))
+
+	logs := bytes.Buffer{}
+	r := newRedactFlow(nil, nil, new(string), new(int), false)
+	r.SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	assert.NoError(t, r.Resume(&state))
+	assert.Empty(t, logs.String())
+}
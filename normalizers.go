@@ -0,0 +1,59 @@
+package strumt
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalizer transforms a user input before it's given
+// to a prompter and recorded in the scenario
+type Normalizer func(string) string
+
+// StripCR removes a trailing carriage return left by
+// inputs using windows line endings
+func StripCR(input string) string {
+	return strings.TrimSuffix(input, "\r")
+}
+
+// TrimSpace removes leading and trailing white spaces
+func TrimSpace(input string) string {
+	return strings.TrimSpace(input)
+}
+
+// NFC converts an input to its unicode normalization form C,
+// so the same characters typed differently are equal
+func NFC(input string) string {
+	return norm.NFC.String(input)
+}
+
+// CollapseSpaces replaces consecutive white spaces with a single space
+func CollapseSpaces(input string) string {
+	var b strings.Builder
+	space := false
+
+	for _, r := range input {
+		if unicode.IsSpace(r) {
+			if !space {
+				b.WriteRune(' ')
+			}
+
+			space = true
+			continue
+		}
+
+		space = false
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func normalize(normalizers []Normalizer, input string) string {
+	for _, n := range normalizers {
+		input = n(input)
+	}
+
+	return input
+}
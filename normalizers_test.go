@@ -0,0 +1,93 @@
+package strumt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizers(t *testing.T) {
+	type scenario struct {
+		name       string
+		normalizer Normalizer
+		input      string
+		expected   string
+	}
+
+	scenarios := []scenario{
+		{"Strip carriage return", StripCR, "test\r", "test"},
+		{"Strip only trailing carriage return", StripCR, "te\rst", "te\rst"},
+		{"Trim spaces", TrimSpace, " \t test \t", "test"},
+		{"Normalize to NFC", NFC, "e\u0301te\u0301", "\u00e9t\u00e9"},
+		{"Collapse spaces", CollapseSpaces, "a  b\t\tc \t d", "a b c d"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			assert.Equal(t, s.expected, s.normalizer(s.input))
+		})
+	}
+}
+
+func TestPromptsRunWithNormalizers(t *testing.T) {
+	type scenario struct {
+		name        string
+		normalizers []Normalizer
+		input       string
+		username    string
+		ips         []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Windows line endings are stripped by default",
+			nil,
+			"user\r\n127.0.0.1\r\n1.2.3.4\r\n\r\n",
+			"user",
+			[]string{"127.0.0.1", "1.2.3.4"},
+		},
+		{
+			"Several normalizers",
+			[]Normalizer{StripCR, TrimSpace, CollapseSpaces},
+			"  John   Doe \r\n 127.0.0.1\r\n  \r\n",
+			"John Doe",
+			[]string{"127.0.0.1"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var username string
+			var ips []string
+
+			p := NewPromptsFromReaderAndWriter(bytes.NewBufferString(s.input), ioutil.Discard)
+
+			if s.normalizers != nil {
+				p.SetNormalizers(s.normalizers...)
+			}
+
+			p.AddLinePrompter(&StringPrompt{&username, "Give a username", "username", "ips", "username"})
+			p.AddMultilinePrompter(&IpsPrompt{&ips, "Give some ips", "ips", "", "ips"})
+			p.SetFirst("username")
+			p.Run()
+
+			assert.Equal(t, s.username, username)
+			assert.Equal(t, s.ips, ips)
+			assert.Equal(t, s.username, p.Scenario()[0].Inputs()[0])
+		})
+	}
+}
+
+func TestPromptsRunWithoutNormalizers(t *testing.T) {
+	var username string
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("user\r\n"), ioutil.Discard)
+	p.SetNormalizers()
+	p.AddLinePrompter(&StringPrompt{&username, "Give a username", "username", "", "username"})
+	p.SetFirst("username")
+	p.Run()
+
+	assert.Equal(t, "user\r", username)
+}
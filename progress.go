@@ -0,0 +1,110 @@
+package strumt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProgressFormatter renders the position of the current
+// prompter among the total number of prompters to run
+type ProgressFormatter func(step int, total int) string
+
+// ProgressCounter renders progress as "[3/7] "
+func ProgressCounter(step int, total int) string {
+	return fmt.Sprintf("[%d/%d] ", step, total)
+}
+
+// ProgressBar renders progress as a bar of the given width, e.g. "[###-------] "
+func ProgressBar(width int) ProgressFormatter {
+	return func(step int, total int) string {
+		done := 0
+
+		if total > 0 {
+			done = width * (step - 1) / total
+		}
+
+		return "[" + strings.Repeat("#", done) + strings.Repeat("-", width-done) + "] "
+	}
+}
+
+// SetProgress defines how progress is rendered in front of the prompt
+// string, nothing is rendered by default. Prompters have to implement
+// TransitionPrompter for the total to be accurate
+func (p *Prompts) SetProgress(formatter ProgressFormatter) {
+	p.progress = formatter
+}
+
+// Progress returns the position of the current prompter and the total
+// number of prompters of the sequence. The total is the number of
+// prompters already run plus the longest path of declared transitions
+// from the current prompter through prompters not run yet, it's
+// updated as the sequence goes on.
+// A prompter which doesn't implement TransitionPrompter is considered
+// as the last one. Both are 0 when the prompt sequence is not running
+func (p *Prompts) Progress() (int, int) {
+	if _, ok := p.prompts[p.current]; !ok {
+		return 0, 0
+	}
+
+	done := 0
+	run := map[string]bool{}
+
+	for _, step := range p.scenario {
+		if step.err == nil && !step.aborted && !step.help {
+			done++
+			run[step.id] = true
+		}
+	}
+
+	delete(run, p.current)
+
+	return done + 1, done + p.longest(p.current, run, map[string]int{})
+}
+
+// longest returns the number of prompters on the longest
+// path of declared transitions starting from key, transitions
+// going back to a prompter of the path or already run are ignored
+func (p *Prompts) longest(key string, path map[string]bool, lengths map[string]int) int {
+	if length, ok := lengths[key]; ok {
+		return length
+	}
+
+	prompt, ok := p.prompts[key]
+
+	if !ok {
+		return 0
+	}
+
+	path[key] = true
+	best := 0
+
+	if t, ok := prompt.(TransitionPrompter); ok {
+		for _, id := range t.Transitions() {
+			next := p.target(key, id, false)
+
+			if path[next] {
+				continue
+			}
+
+			if length := p.longest(next, path, lengths); length > best {
+				best = length
+			}
+		}
+	}
+
+	delete(path, key)
+	lengths[key] = best + 1
+
+	return best + 1
+}
+
+// promptString returns the prompt string of prompt preceded by progress
+func (p *Prompts) promptString(prompt Prompter) string {
+	if p.progress == nil {
+		return prompt.PromptString()
+	}
+
+	step, total := p.Progress()
+
+	return p.progress(step, total) + prompt.PromptString()
+}
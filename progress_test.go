@@ -0,0 +1,173 @@
+package strumt
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type StepPrompt struct {
+	id       string
+	branches map[string]string
+	next     string
+}
+
+func (s *StepPrompt) ID() string {
+	return s.id
+}
+
+func (s *StepPrompt) PromptString() string {
+	return "Give " + s.id
+}
+
+func (s *StepPrompt) Parse(value string) error {
+	return nil
+}
+
+func (s *StepPrompt) NextOnSuccess(value string) string {
+	if next, ok := s.branches[value]; ok {
+		return next
+	}
+
+	return s.next
+}
+
+func (s *StepPrompt) NextOnError(err error) string {
+	return s.id
+}
+
+func (s *StepPrompt) Transitions() []string {
+	transitions := []string{s.next}
+
+	for _, next := range s.branches {
+		transitions = append(transitions, next)
+	}
+
+	return transitions
+}
+
+func newProgressFlow() Prompts {
+	p := NewPromptsFromReaderAndWriter(nil, nil)
+	p.AddLinePrompter(&StepPrompt{"name", map[string]string{"admin": "rights"}, "age"})
+	p.AddLinePrompter(&StepPrompt{"rights", map[string]string{"all": "name"}, "age"})
+	p.AddLinePrompter(&StepPrompt{"age", nil, ""})
+	p.SetFirst("name")
+
+	return p
+}
+
+func TestPromptsProgress(t *testing.T) {
+	type scenario struct {
+		name     string
+		inputs   []string
+		progress [][2]int
+	}
+
+	scenarios := []scenario{
+		{
+			"Shortest path",
+			[]string{"john", "31"},
+			[][2]int{{1, 3}, {2, 2}, {0, 0}},
+		},
+		{
+			"Longest path",
+			[]string{"admin", "read", "31"},
+			[][2]int{{1, 3}, {2, 3}, {3, 3}, {0, 0}},
+		},
+		{
+			"Going back",
+			[]string{"admin", "all", "john", "31"},
+			[][2]int{{1, 3}, {2, 3}, {3, 4}, {4, 4}, {0, 0}},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := newProgressFlow()
+
+			step, total := p.Progress()
+			assert.Equal(t, [2]int{0, 0}, [2]int{step, total})
+
+			p.Start()
+
+			for i, input := range s.inputs {
+				step, total := p.Progress()
+				assert.Equal(t, s.progress[i], [2]int{step, total})
+
+				p.Submit([]string{input})
+			}
+
+			step, total = p.Progress()
+			assert.Equal(t, s.progress[len(s.inputs)], [2]int{step, total})
+		})
+	}
+}
+
+func TestPromptsProgressWithGroupAndUndeclaredTransitions(t *testing.T) {
+	connection := Connection{}
+
+	p := NewPromptsFromReaderAndWriter(nil, nil)
+	p.AddGroup("databases", NewGroup(newConnectionFlow(&connection), 0, 0), "username")
+	p.AddLinePrompter(&StepPrompt{"username", nil, "password"})
+	p.AddLinePrompter(&StringPrompt{new(string), "Give a password", "password", "whatever", "password"})
+	p.SetFirst("username")
+	p.Start()
+
+	step, total := p.Progress()
+	assert.Equal(t, [2]int{1, 2}, [2]int{step, total})
+
+	p.SetFirst("databases")
+	p.Start()
+
+	step, total = p.Progress()
+	assert.Equal(t, [2]int{1, 3}, [2]int{step, total})
+
+	// prompters of the connection flow don't declare transitions
+	p.Submit([]string{"y"})
+
+	step, total = p.Progress()
+	assert.Equal(t, [2]int{2, 2}, [2]int{step, total})
+
+	p.Submit([]string{"db1"})
+	p.Submit([]string{"5432"})
+
+	step, total = p.Progress()
+	assert.Equal(t, [2]int{4, 6}, [2]int{step, total})
+}
+
+func TestPromptsRunWithProgress(t *testing.T) {
+	type scenario struct {
+		name      string
+		formatter ProgressFormatter
+		output    string
+	}
+
+	scenarios := []scenario{
+		{
+			"Counter",
+			ProgressCounter,
+			"[1/3] Give name\n\n[2/3] Give rights\n\n[3/3] Give age\n",
+		},
+		{
+			"Bar",
+			ProgressBar(6),
+			"[------] Give name\n\n[##----] Give rights\n\n[####--] Give age\n",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			output := bytes.Buffer{}
+
+			p := newProgressFlow()
+			p.reader = bufio.NewReader(bytes.NewBufferString("admin\nread\n31\n"))
+			p.writer = &output
+			p.SetProgress(s.formatter)
+			p.Run()
+
+			assert.Equal(t, s.output, output.String())
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package strumt_test
+
+import (
+	"fmt"
+
+	"github.com/antham/strumt/v2"
+)
+
+func Example_stateMachine() {
+	user := User{}
+	inputs := []string{"Brad", "", "Blanton", "whatever", "31"}
+
+	p := strumt.NewPromptsFromReaderAndWriter(nil, nil)
+	p.AddLinePrompter(&StringPrompt{&user.FirstName, "Enter your first name", "userName", "lastName", "userName"})
+	p.AddLinePrompter(&StringPrompt{&user.LastName, "Enter your last name", "lastName", "age", "lastName"})
+	p.AddLinePrompter(&IntPrompt{&user.Age, "Enter your age", "age", "", "age"})
+	p.SetFirst("userName")
+
+	for state := p.Start(); !state.Done(); inputs = inputs[1:] {
+		if state.Error() != nil {
+			fmt.Println(state.Error())
+		}
+
+		fmt.Printf("%s : %q\n", state.Prompter().PromptString(), inputs[0])
+
+		state = p.Submit(inputs[:1])
+	}
+
+	fmt.Printf("User datas : %#v", user)
+
+	// Output:
+	// Enter your first name : "Brad"
+	// Enter your last name : ""
+	// Empty value given
+	// Enter your last name : "Blanton"
+	// Enter your age : "whatever"
+	// whatever is not a valid number
+	// Enter your age : "31"
+	// User datas : strumt_test.User{FirstName:"Brad", LastName:"Blanton", Age:31}
+}
@@ -6,8 +6,21 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"strings"
+	"time"
+)
+
+// Source defines where the inputs of a step come from
+type Source string
+
+// Sources of step inputs
+const (
+	SourceUser    Source = "user"
+	SourceDefault Source = "default"
+	SourceEnv     Source = "env"
+	SourceFile    Source = "file"
 )
 
 // Step represents a scenario step which is
@@ -15,9 +28,24 @@ import (
 // the prompt string displayed on the screen, inputs that the user has given,
 // and the prompt error if one occurred
 type Step struct {
-	prompt string
-	inputs []string
-	err    error
+	id      string
+	prompt  string
+	inputs  []string
+	err     error
+	start   time.Time
+	end     time.Time
+	next    string
+	target  string
+	source  Source
+	attempt int
+	aborted bool
+	help    bool
+}
+
+// ID returns the ID of the prompter, prompters of a subflow
+// are referenced using "subflowID.prompterID"
+func (s Step) ID() string {
+	return s.id
 }
 
 // PromptString returns the prompt string displayed by the prompt on the screen
@@ -35,14 +63,88 @@ func (s Step) Error() error {
 	return s.err
 }
 
+// Start returns when the prompt started
+func (s Step) Start() time.Time {
+	return s.start
+}
+
+// End returns when the prompt ended
+func (s Step) End() time.Time {
+	return s.end
+}
+
+// Next returns the ID of the prompter run after this step,
+// an empty string means the prompt sequence ended
+func (s Step) Next() string {
+	return s.next
+}
+
+// Target returns the ID of the prompter requested after this step,
+// it differs from Next when no prompter is registered under this ID
+func (s Step) Target() string {
+	return s.target
+}
+
+// Source returns where inputs come from
+func (s Step) Source() Source {
+	return s.source
+}
+
+// Attempt returns how many times in a row the prompter has been run,
+// it starts at 1 and is increased each time the previous step of the
+// same prompter failed
+func (s Step) Attempt() int {
+	return s.attempt
+}
+
+// Aborted returns true when the inputs of the prompter couldn't
+// be read (e.g. the reader reached EOF), which ended the sequence
+func (s Step) Aborted() bool {
+	return s.aborted
+}
+
+// HelpRequested returns true when the user asked for the help
+// of the prompter, such a step is not counted as an attempt
+func (s Step) HelpRequested() bool {
+	return s.help
+}
+
+// Answer represents the last inputs accepted by a prompter
+type Answer struct {
+	inputs []string
+	value  interface{}
+}
+
+// Inputs retrieves inputs accepted by the prompter
+func (a Answer) Inputs() []string {
+	return a.inputs
+}
+
+// Value returns the value provided by the prompter when it implements
+// ValuePrompter, nil otherwise
+func (a Answer) Value() interface{} {
+	return a.value
+}
+
 // NewPrompts creates a new prompt from stdin and stdout
 func NewPrompts() Prompts {
-	return Prompts{reader: bufio.NewReader(os.Stdin), writer: os.Stdout, prompts: map[string]Prompter{}}
+	return NewPromptsFromReaderAndWriter(os.Stdin, os.Stdout)
 }
 
 // NewPromptsFromReaderAndWriter creates a new prompt from a given reader and writer, useful for testing purpose
 func NewPromptsFromReaderAndWriter(reader io.Reader, writer io.Writer) Prompts {
-	return Prompts{reader: bufio.NewReader(reader), writer: writer, prompts: map[string]Prompter{}}
+	return Prompts{
+		reader:      bufio.NewReader(reader),
+		writer:      writer,
+		prompts:     map[string]Prompter{},
+		scopes:      map[string]scope{},
+		entries:     map[string]string{},
+		groups:      map[string]*morePrompt{},
+		normalizers: []Normalizer{StripCR},
+		helpTrigger: "?",
+		session:     newSession(),
+		now:         time.Now,
+	}
 }
 
 // Prompts is the main structure that handle all defined prompts
@@ -50,53 +152,122 @@ func NewPromptsFromReaderAndWriter(reader io.Reader, writer io.Writer) Prompts {
 // It keeps a record as well, of all user actions under a scenario
 // entry
 type Prompts struct {
-	currentPrompt Prompter
+	first         string
+	current       string
 	prompts       map[string]Prompter
+	scopes        map[string]scope
+	entries       map[string]string
+	groups        map[string]*morePrompt
 	reader        *bufio.Reader
 	writer        io.Writer
 	scenario      []Step
+	normalizers   []Normalizer
+	editorCommand string
+	session       *Session
+	now           func() time.Time
+	since         time.Time
+	resumed       bool
+	logger        *slog.Logger
+	progress      ProgressFormatter
+	review        bool
+	reviewFrom    int
+	helpTrigger   string
 }
 
-func (p *Prompts) parse() ([]string, Prompter, error) {
-	var nextPrompt Prompter
-	var inputs []string
-	var err error
+func (p *Prompts) read() ([]string, Source, error) {
+	switch prompt := p.prompts[p.current].(type) {
+	case EditorPrompter:
+		inputs, err := p.readFromEditor(prompt)
 
-	switch prompt := p.currentPrompt.(type) {
+		return inputs, SourceFile, err
 	case LinePrompter:
-		var input string
-
-		input, err = parseLine(p.reader, prompt)
+		input, err := readLine(p.reader, p.normalizers)
 
-		if prompt.NextOnSuccess(input) != "" {
-			nextPrompt = p.prompts[prompt.NextOnSuccess(input)]
+		if err != nil {
+			return []string{}, SourceUser, err
 		}
 
-		inputs = append(inputs, input)
+		return []string{input}, SourceUser, nil
 	case MultilinePrompter:
-		inputs, err = parseMultipleLine(p.reader, prompt)
+		terminator := ""
 
-		if prompt.NextOnSuccess(inputs) != "" {
-			nextPrompt = p.prompts[prompt.NextOnSuccess(inputs)]
+		if t, ok := prompt.(MultilineTerminator); ok {
+			terminator = t.Terminator()
+		}
+
+		inputs, err := readMultipleLine(p.reader, p.normalizers, terminator)
+
+		return inputs, SourceUser, err
+	}
+
+	return []string{}, SourceUser, nil
+}
+
+func (p *Prompts) parse(inputs []string) (string, error) {
+	var nextID string
+	var err error
+
+	switch prompt := p.prompts[p.current].(type) {
+	case LinePrompter:
+		if err = prompt.Parse(inputs[0]); err == nil {
+			nextID = prompt.NextOnSuccess(inputs[0])
+		}
+	case MultilinePrompter:
+		if err = prompt.Parse(inputs); err == nil {
+			nextID = prompt.NextOnSuccess(inputs)
 		}
 	}
 
 	if err != nil {
-		nextPrompt = p.prompts[p.currentPrompt.NextOnError(err)]
+		nextID = p.prompts[p.current].NextOnError(err)
+	} else {
+		p.session.answers[p.current] = newAnswer(p.prompts[p.current], inputs)
+		p.collect(p.current, inputs)
+	}
+
+	return p.resolve(p.current, nextID, err != nil), err
+}
+
+func newAnswer(prompt Prompter, inputs []string) Answer {
+	answer := Answer{inputs: inputs}
+
+	if v, ok := prompt.(ValuePrompter); ok {
+		answer.value = v.Value()
+	}
+
+	return answer
+}
+
+// lastStep returns the last step of the scenario, if any,
+// help requests are ignored
+func (p *Prompts) lastStep() (Step, bool) {
+	for i := len(p.scenario) - 1; i >= 0; i-- {
+		if !p.scenario[i].help {
+			return p.scenario[i], true
+		}
 	}
 
-	return inputs, nextPrompt, err
+	return Step{}, false
 }
 
-func (p *Prompts) appendScenario(promptString string, inputs []string, err error) {
-	p.scenario = append(
-		p.scenario,
-		Step{
-			promptString,
-			inputs,
-			err,
-		},
-	)
+func (p *Prompts) attempt() int {
+	if last, ok := p.lastStep(); ok && last.id == p.current && last.err != nil {
+		return last.attempt + 1
+	}
+
+	return 1
+}
+
+// visited reports whether the prompter was already
+// reached earlier in the prompt sequence
+func (p *Prompts) visited(id string) bool {
+	for _, step := range p.scenario {
+		if step.id == id {
+			return true
+		}
+	}
+
+	return false
 }
 
 // AddLinePrompter adds a new LinePrompter using the internal id as a reference
@@ -109,104 +280,185 @@ func (p *Prompts) AddMultilinePrompter(prompt MultilinePrompter) {
 	p.prompts[prompt.ID()] = prompt
 }
 
+// SetNormalizers defines the normalizers applied in order to every input
+// before it's given to a prompter and recorded in the scenario, by default
+// only StripCR is applied. Calling SetNormalizers without argument
+// disables normalization
+func (p *Prompts) SetNormalizers(normalizers ...Normalizer) {
+	p.normalizers = normalizers
+}
+
+// SetEditor defines the command used to open an editor
+// for EditorPrompter, it overrides VISUAL and EDITOR
+// environment variables
+func (p *Prompts) SetEditor(command string) {
+	p.editorCommand = command
+}
+
+// SetHelpTrigger defines the input showing the help text of a
+// prompter implementing Helper, "?" by default. An empty trigger
+// disables help so the input is always given to Parse
+func (p *Prompts) SetHelpTrigger(trigger string) {
+	p.helpTrigger = trigger
+}
+
 // SetFirst defines from which prompt the prompt sequence has to start
 func (p *Prompts) SetFirst(id string) {
-	p.currentPrompt = p.prompts[id]
+	p.first = id
 }
 
-// Scenario retrieves all steps done during a prompt sequence
+// Current returns the ID of the prompter currently run,
+// an empty string is returned when the prompt sequence is not running
+func (p *Prompts) Current() string {
+	return p.current
+}
+
+// Prompter retrieves the prompter registered under the given ID,
+// prompters of a subflow are referenced using "subflowID.prompterID"
+func (p *Prompts) Prompter(id string) (Prompter, bool) {
+	prompt, ok := p.prompts[id]
+
+	return prompt, ok
+}
+
+// Session retrieves the session shared by prompters, answers
+// are cleared each time the prompt sequence is run while values
+// are kept, so the session can be filled before running the sequence
+func (p *Prompts) Session() *Session {
+	return p.session
+}
+
+// Answers retrieves the last inputs accepted by each prompter
+// during a prompt sequence, keyed by prompter ID. Contrary to
+// Scenario, failed attempts are not part of answers
+func (p *Prompts) Answers() map[string]Answer {
+	answers := map[string]Answer{}
+
+	for id, answer := range p.session.answers {
+		answers[id] = answer
+	}
+
+	return answers
+}
+
+// Scenario retrieves all steps done during a prompt sequence,
+// inputs of sensitive prompters are redacted
 func (p *Prompts) Scenario() []Step {
-	return p.scenario
+	steps := make([]Step, len(p.scenario))
+
+	for i, step := range p.scenario {
+		steps[i] = p.redact(step)
+	}
+
+	return steps
 }
 
-// Run executes a prompt sequence
+// Run executes a prompt sequence, it stops when a prompter
+// ends the sequence or when reading user input fails (e.g. when
+// the reader reaches EOF), in that case the last step
+// records the read error. After Resume, it goes on from
+// where the sequence stopped. When review is enabled, accepted
+// answers are shown before returning so the user can change them
 func (p *Prompts) Run() {
-	p.scenario = []Step{}
+	var state State
+
+	if p.resumed {
+		p.resumed = false
+		state = p.state()
+	} else {
+		state = p.Start()
+		p.renderSteps(0)
+	}
 
-	for {
-		prompt := p.currentPrompt
-		renderPrompt(p.writer, prompt)
+	for !state.Done() {
+		renderPrompt(p.writer, state.Prompter(), p.promptString(state.Prompter()))
 
-		inputs, nextPrompt, err := p.parse()
+		from := len(p.scenario)
+		inputs, source, err := p.read()
 
 		if err != nil {
-			renderError(p.writer, prompt, err)
-		}
-
-		p.appendScenario(prompt.PromptString(), inputs, err)
-
-		if nextPrompt == nil {
+			p.abort(inputs, err)
 			return
 		}
 
-		renderSeparator(p.writer, prompt)
+		state = p.submit(inputs, source)
+		p.renderSteps(from)
+	}
 
-		p.currentPrompt = nextPrompt
+	if p.review && state.Error() == nil {
+		p.reviewAnswers()
 	}
 }
 
-func isMultilineEnd(reader io.ByteScanner) (bool, error) {
-	bn, err := reader.ReadByte()
+// renderSteps renders errors, help texts and separators
+// of steps recorded from the given index
+func (p *Prompts) renderSteps(from int) {
+	for _, step := range p.scenario[from:] {
+		prompt := p.prompts[step.id]
+		step = p.redact(step)
 
-	if err == io.EOF {
-		return true, nil
-	}
+		if step.err != nil {
+			renderError(p.writer, prompt, step.err)
+		}
 
-	if bn == '\n' {
-		return true, nil
-	}
+		if h, ok := prompt.(Helper); ok && step.help {
+			renderHelp(p.writer, prompt, h.Help())
+		}
 
-	if err := reader.UnreadByte(); err != nil {
-		return false, err
+		// nothing is displayed when an input is
+		// successfully taken from the environment
+		if step.next != "" && (step.source != SourceEnv || step.err != nil) {
+			renderSeparator(p.writer, prompt)
+		}
 	}
-
-	return false, nil
 }
 
-func parseMultipleLine(reader *bufio.Reader, prompt MultilinePrompter) ([]string, error) {
+func readMultipleLine(reader *bufio.Reader, normalizers []Normalizer, terminator string) ([]string, error) {
 	inputs := []string{}
 
 	for {
 		input, err := reader.ReadString('\n')
-		input = strings.TrimRight(input, "\n")
+		input = normalize(normalizers, strings.TrimRight(input, "\n"))
 
-		if err != nil {
+		if err != nil && (err != io.EOF || (input == "" && len(inputs) == 0)) {
 			return []string{}, err
 		}
 
-		inputs = append(inputs, input)
+		// when no terminator is defined, an empty line ends the input
+		// except the first line which is always kept
+		if input == terminator && (terminator != "" || len(inputs) > 0) {
+			return inputs, nil
+		}
 
-		end, err := isMultilineEnd(reader)
+		if err == io.EOF {
+			if input != "" {
+				inputs = append(inputs, input)
+			}
 
-		if err != nil {
-			return []string{}, err
+			return inputs, nil
 		}
 
-		if end {
-			break
-		}
+		inputs = append(inputs, input)
 	}
-
-	return inputs, prompt.Parse(inputs)
 }
 
-func parseLine(reader *bufio.Reader, prompt LinePrompter) (string, error) {
+func readLine(reader *bufio.Reader, normalizers []Normalizer) (string, error) {
 	input, err := reader.ReadString('\n')
 	input = strings.TrimRight(input, "\n")
 
-	if err != nil {
+	if err != nil && (err != io.EOF || input == "") {
 		return "", err
 	}
 
-	return input, prompt.Parse(input)
+	return normalize(normalizers, input), nil
 }
 
-func renderPrompt(writer io.Writer, prompt Prompter) {
+func renderPrompt(writer io.Writer, prompt Prompter, promptString string) {
 	switch pr := prompt.(type) {
 	case PromptRenderer:
-		pr.PrintPrompt(writer, prompt.PromptString())
+		pr.PrintPrompt(writer, promptString)
 	default:
-		fmt.Fprintf(writer, "%s\n", prompt.PromptString())
+		fmt.Fprintf(writer, "%s\n", promptString)
 	}
 }
 
@@ -219,6 +471,15 @@ func renderError(writer io.Writer, prompt Prompter, err error) {
 	}
 }
 
+func renderHelp(writer io.Writer, prompt Prompter, help string) {
+	switch pr := prompt.(type) {
+	case HelpRenderer:
+		pr.PrintHelp(writer, help)
+	default:
+		fmt.Fprintf(writer, "%s\n", help)
+	}
+}
+
 func renderSeparator(writer io.Writer, prompt Prompter) {
 	switch pr := prompt.(type) {
 	case SeparatorRenderer:
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -304,70 +305,70 @@ func TestPromptsScenario(t *testing.T) {
 
 	expectedScenario := []Step{
 		{
-			"Give a username",
-			[]string{""},
-			fmt.Errorf("Empty value given"),
+			prompt: "Give a username",
+			inputs: []string{""},
+			err:    fmt.Errorf("Empty value given"),
 		},
 		{
-			"Give a username",
-			[]string{"user"},
-			nil,
+			prompt: "Give a username",
+			inputs: []string{"user"},
+			err:    nil,
 		},
 		{
-			"Give a password",
-			[]string{""},
-			fmt.Errorf("Empty value given"),
+			prompt: "Give a password",
+			inputs: []string{""},
+			err:    fmt.Errorf("Empty value given"),
 		},
 		{
-			"Give a password",
-			[]string{"password"},
-			nil,
+			prompt: "Give a password",
+			inputs: []string{"password"},
+			err:    nil,
 		},
 		{
-			"Give a port",
-			[]string{"test"},
-			fmt.Errorf("Provide a numerical value"),
+			prompt: "Give a port",
+			inputs: []string{"test"},
+			err:    fmt.Errorf("Provide a numerical value"),
 		},
 		{
-			"Give a port",
-			[]string{"10000"},
-			nil,
+			prompt: "Give a port",
+			inputs: []string{"10000"},
+			err:    nil,
 		},
 		{
-			"Give some ips",
-			[]string{
+			prompt: "Give some ips",
+			inputs: []string{
 				"127.0.0.1",
 				"test",
 				"1.2.3.4",
 				"8.9.10.11",
 			},
-			fmt.Errorf("test is not a valid IP"),
+			err: fmt.Errorf("test is not a valid IP"),
 		},
 		{
-			"Give some ips",
-			[]string{
+			prompt: "Give some ips",
+			inputs: []string{
 				"127.0.0.1",
 				"1.2.3.4",
 				"8.9.10.11",
 			},
-			nil,
+			err: nil,
 		},
 		{
-			"Give some host/ip couples",
-			[]string{
+			prompt: "Give some host/ip couples",
+			inputs: []string{
 				"localhost:127.0.0.1",
 				"test",
 				"myIp:1.2.3.4",
 			},
-			fmt.Errorf("Check test is a valid couple key:value"),
+			err: fmt.Errorf("Check test is a valid couple key:value"),
 		},
 		{
-			"Give some host/ip couples",
-			[]string{
+			prompt: "Give some host/ip couples",
+			inputs: []string{
 				"localhost:127.0.0.1",
 				"myIp:1.2.3.4",
 			},
-			nil,
+			err: nil,
 		},
 	}
 
@@ -384,3 +385,279 @@ func TestPromptsScenario(t *testing.T) {
 		}
 	}
 }
+
+func TestPromptsRunStopsOnReadError(t *testing.T) {
+	var actualStdout bytes.Buffer
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("\n"), &actualStdout)
+
+	p.AddLinePrompter(&StringPrompt{new(string), "Give a username : ", "username", "", "username"})
+
+	p.SetFirst("username")
+	p.Run()
+
+	scenario := p.Scenario()
+
+	assert.Len(t, scenario, 2)
+	assert.EqualError(t, scenario[0].Error(), "Empty value given")
+	assert.Equal(t, []string{}, scenario[1].Inputs())
+	assert.Equal(t, io.EOF, scenario[1].Error())
+	assert.Equal(t, "Give a username : \nEmpty value given\n\nGive a username : \n", actualStdout.String())
+}
+
+type TextPrompt struct {
+	valuePtr   *[]string
+	terminator string
+}
+
+func (t *TextPrompt) ID() string {
+	return "text"
+}
+
+func (t *TextPrompt) PromptString() string {
+	return "Give a text"
+}
+
+func (t *TextPrompt) Parse(values []string) error {
+	*(t.valuePtr) = values
+
+	return nil
+}
+
+func (t *TextPrompt) NextOnSuccess(values []string) string {
+	return ""
+}
+
+func (t *TextPrompt) NextOnError(err error) string {
+	return "text"
+}
+
+func (t *TextPrompt) Terminator() string {
+	return t.terminator
+}
+
+func TestPromptsRunMultilineInputs(t *testing.T) {
+	type scenario struct {
+		name     string
+		input    string
+		prompter func(*[]string) MultilinePrompter
+		expected []string
+		err      error
+	}
+
+	scenarios := []scenario{
+		{
+			"Empty line ends input",
+			"test1\ntest2\n\ntest3\n",
+			func(values *[]string) MultilinePrompter { return &TextPrompt{values, ""} },
+			[]string{"test1", "test2"},
+			nil,
+		},
+		{
+			"First line can be empty",
+			"\ntest1\n\n",
+			func(values *[]string) MultilinePrompter { return &TextPrompt{values, ""} },
+			[]string{"", "test1"},
+			nil,
+		},
+		{
+			"End of input ends input",
+			"test1\ntest2",
+			func(values *[]string) MultilinePrompter { return &TextPrompt{values, ""} },
+			[]string{"test1", "test2"},
+			nil,
+		},
+		{
+			"End of input with a trailing new line ends input",
+			"test1\ntest2\n",
+			func(values *[]string) MultilinePrompter { return &TextPrompt{values, ""} },
+			[]string{"test1", "test2"},
+			nil,
+		},
+		{
+			"Custom terminator ends input",
+			"test1\n\ntest2\n\n.\ntest3\n",
+			func(values *[]string) MultilinePrompter { return &TextPrompt{values, "."} },
+			[]string{"test1", "", "test2", ""},
+			nil,
+		},
+		{
+			"End of input ends input with a custom terminator",
+			"test1\n\ntest2",
+			func(values *[]string) MultilinePrompter { return &TextPrompt{values, "EOF"} },
+			[]string{"test1", "", "test2"},
+			nil,
+		},
+		{
+			"Empty input",
+			"",
+			func(values *[]string) MultilinePrompter { return &TextPrompt{values, "."} },
+			[]string{},
+			io.EOF,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			values := []string{}
+			prompter := s.prompter(&values)
+
+			p := NewPromptsFromReaderAndWriter(bytes.NewBufferString(s.input), ioutil.Discard)
+			p.AddMultilinePrompter(prompter)
+			p.SetFirst(prompter.ID())
+			p.Run()
+
+			scenario := p.Scenario()
+
+			assert.Equal(t, s.expected, values)
+			assert.Equal(t, s.err, scenario[len(scenario)-1].Error())
+		})
+	}
+}
+
+func TestPromptsRunLineWithoutTrailingNewLine(t *testing.T) {
+	var value string
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("user"), ioutil.Discard)
+	p.AddLinePrompter(&StringPrompt{&value, "Give a username", "username", "", "username"})
+	p.SetFirst("username")
+	p.Run()
+
+	assert.Equal(t, "user", value)
+	assert.Len(t, p.Scenario(), 1)
+	assert.NoError(t, p.Scenario()[0].Error())
+}
+
+type TypedIntPrompt struct {
+	IntPrompt
+}
+
+func (t *TypedIntPrompt) Value() interface{} {
+	return *t.valuePtr
+}
+
+func TestPromptsAnswers(t *testing.T) {
+	buf := "\nuser\ntest\n10000\n127.0.0.1\ntest\n\n127.0.0.1\n\n"
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString(buf), ioutil.Discard)
+
+	p.AddLinePrompter(&StringPrompt{new(string), "Give a username", "username", "port", "username"})
+	p.AddLinePrompter(&TypedIntPrompt{IntPrompt{new(int), "Give a port", "port", "ips", "port"}})
+	p.AddMultilinePrompter(&IpsPrompt{&[]string{}, "Give some ips", "ips", "", "ips"})
+	p.AddLinePrompter(&StringPrompt{new(string), "Give a password", "password", "", "password"})
+
+	p.SetFirst("username")
+	p.Run()
+
+	answers := p.Answers()
+
+	assert.Len(t, answers, 3)
+	assert.Equal(t, []string{"user"}, answers["username"].Inputs())
+	assert.Nil(t, answers["username"].Value())
+	assert.Equal(t, []string{"10000"}, answers["port"].Inputs())
+	assert.Equal(t, 10000, answers["port"].Value())
+	assert.Equal(t, []string{"127.0.0.1"}, answers["ips"].Inputs())
+	assert.Len(t, p.Scenario(), 6)
+}
+
+type DefaultStringPrompt struct {
+	StringPrompt
+	defaultValue string
+	env          string
+}
+
+func (d *DefaultStringPrompt) Default() string {
+	return d.defaultValue
+}
+
+func (d *DefaultStringPrompt) Env() string {
+	return d.env
+}
+
+func TestPromptsScenarioStepDetails(t *testing.T) {
+	t.Setenv("STRUMT_TEST_PORT", "whatever")
+	t.Setenv("STRUMT_TEST_IPS", "127.0.0.1\n1.2.3.4")
+
+	var stdout bytes.Buffer
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("\n\n10000\n"), &stdout)
+
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	p.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	p.AddLinePrompter(&DefaultStringPrompt{StringPrompt{new(string), "Give a host", "host", "port", "host"}, "localhost", ""})
+	p.AddLinePrompter(&EnvIntPrompt{IntPrompt{new(int), "Give a port", "port", "ips", "port"}, "STRUMT_TEST_PORT"})
+	p.AddMultilinePrompter(&EnvIpsPrompt{IpsPrompt{&[]string{}, "Give some ips", "ips", "", "ips"}, "STRUMT_TEST_IPS"})
+	p.SetFirst("host")
+	p.Run()
+
+	type step struct {
+		id      string
+		inputs  []string
+		next    string
+		source  Source
+		attempt int
+		start   int
+		end     int
+	}
+
+	expected := []step{
+		{"host", []string{"localhost"}, "port", SourceDefault, 1, 1, 2},
+		{"port", []string{"whatever"}, "port", SourceEnv, 1, 2, 3},
+		{"port", []string{""}, "port", SourceUser, 2, 3, 4},
+		{"port", []string{"10000"}, "ips", SourceUser, 3, 4, 5},
+		{"ips", []string{"127.0.0.1", "1.2.3.4"}, "", SourceEnv, 1, 5, 6},
+	}
+
+	actual := []step{}
+
+	for _, s := range p.Scenario() {
+		actual = append(actual, step{s.ID(), s.Inputs(), s.Next(), s.Source(), s.Attempt(), s.Start().Second(), s.End().Second()})
+	}
+
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, "Give a host\n\nProvide a numerical value\n\nGive a port\nProvide a numerical value\n\nGive a port\n\n", stdout.String())
+}
+
+func TestPromptsReadsEnvironmentOnce(t *testing.T) {
+	t.Setenv("STRUMT_TEST_HOST", "example.com")
+
+	p := NewPromptsFromReaderAndWriter(nil, io.Discard)
+	p.AddLinePrompter(&DefaultStringPrompt{StringPrompt{new(string), "Give a host", "host", "host", "host"}, "", "STRUMT_TEST_HOST"})
+	p.SetFirst("host")
+
+	state := p.Start()
+
+	assert.False(t, state.Done())
+	assert.Equal(t, "host", state.ID())
+	assert.Len(t, p.Scenario(), 1)
+	assert.Equal(t, SourceEnv, p.Scenario()[0].Source())
+
+	state = p.Submit([]string{"localhost"})
+
+	assert.False(t, state.Done())
+	assert.Equal(t, "host", state.ID())
+	assert.Len(t, p.Scenario(), 2)
+	assert.Equal(t, SourceUser, p.Scenario()[1].Source())
+}
+
+type EnvIntPrompt struct {
+	IntPrompt
+	env string
+}
+
+func (e *EnvIntPrompt) Env() string {
+	return e.env
+}
+
+type EnvIpsPrompt struct {
+	IpsPrompt
+	env string
+}
+
+func (e *EnvIpsPrompt) Env() string {
+	return e.env
+}
@@ -0,0 +1,60 @@
+package strumt
+
+import "strings"
+
+// Redacted replaces inputs of sensitive prompters
+const Redacted = "********"
+
+func isSensitive(prompt Prompter) bool {
+	s, ok := prompt.(Sensitive)
+
+	return ok && s.Sensitive()
+}
+
+// redactedError hides inputs contained in the message of an error,
+// it doesn't unwrap to the original error which would disclose them
+type redactedError struct {
+	msg string
+}
+
+func (r redactedError) Error() string {
+	return r.msg
+}
+
+func redactError(err error, inputs []string) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+
+	for _, input := range inputs {
+		if input != "" {
+			msg = strings.ReplaceAll(msg, input, Redacted)
+		}
+	}
+
+	if msg == err.Error() {
+		return err
+	}
+
+	return redactedError{msg}
+}
+
+// redact hides inputs of a step run by a sensitive prompter
+func (p *Prompts) redact(step Step) Step {
+	if !isSensitive(p.prompts[step.id]) {
+		return step
+	}
+
+	inputs := make([]string, len(step.inputs))
+
+	for i := range inputs {
+		inputs[i] = Redacted
+	}
+
+	step.err = redactError(step.err, step.inputs)
+	step.inputs = inputs
+
+	return step
+}
@@ -0,0 +1,134 @@
+package strumt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type PasswordPrompt struct {
+	store     *string
+	sensitive bool
+}
+
+func (p *PasswordPrompt) ID() string {
+	return "password"
+}
+
+func (p *PasswordPrompt) PromptString() string {
+	return "Give a password"
+}
+
+func (p *PasswordPrompt) Parse(value string) error {
+	if len(value) < 8 {
+		return fmt.Errorf("%s is too short", value)
+	}
+
+	*(p.store) = value
+
+	return nil
+}
+
+func (p *PasswordPrompt) NextOnSuccess(value string) string {
+	return "port"
+}
+
+func (p *PasswordPrompt) NextOnError(err error) string {
+	return "password"
+}
+
+func (p *PasswordPrompt) Sensitive() bool {
+	return p.sensitive
+}
+
+func newRedactFlow(reader io.Reader, writer io.Writer, password *string, port *int, sensitive bool) Prompts {
+	p := NewPromptsFromReaderAndWriter(reader, writer)
+	p.AddLinePrompter(&StringPrompt{new(string), "Give a username", "username", "password", "username"})
+	p.AddLinePrompter(&PasswordPrompt{password, sensitive})
+	p.AddLinePrompter(&IntPrompt{port, "Give a port", "port", "", "port"})
+	p.SetFirst("username")
+
+	return p
+}
+
+func TestPromptsRunWithSensitivePrompter(t *testing.T) {
+	type scenario struct {
+		name      string
+		sensitive bool
+		output    string
+		inputs    [][]string
+		errors    []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Sensitive prompter",
+			true,
+			"Give a username\n\nGive a password\n******** is too short\n\nGive a password\n\nGive a port\n",
+			[][]string{{"user"}, {Redacted}, {Redacted}, {"10000"}},
+			[]string{"", "******** is too short", "", ""},
+		},
+		{
+			"Prompter no longer sensitive",
+			false,
+			"Give a username\n\nGive a password\nabc is too short\n\nGive a password\n\nGive a port\n",
+			[][]string{{"user"}, {"abc"}, {"s3cr3t-pa55"}, {"10000"}},
+			[]string{"", "abc is too short", "", ""},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var password string
+			var port int
+
+			output := bytes.Buffer{}
+			p := newRedactFlow(bytes.NewBufferString("user\nabc\ns3cr3t-pa55\n10000\n"), &output, &password, &port, s.sensitive)
+			p.Run()
+
+			assert.Equal(t, s.output, output.String())
+			assert.Equal(t, "s3cr3t-pa55", password)
+			assert.Equal(t, []string{"s3cr3t-pa55"}, p.Answers()["password"].Inputs())
+
+			for i, step := range p.Scenario() {
+				assert.Equal(t, s.inputs[i], step.Inputs())
+
+				if s.errors[i] == "" {
+					assert.NoError(t, step.Error())
+				} else {
+					assert.EqualError(t, step.Error(), s.errors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPromptsSaveAndResumeWithSensitivePrompter(t *testing.T) {
+	var password string
+	var port int
+
+	p := newRedactFlow(bytes.NewBufferString("user\nxyz\ns3cr3t-pa55\n10000\n"), &bytes.Buffer{}, &password, &port, true)
+	p.Run()
+
+	state := bytes.Buffer{}
+	assert.NoError(t, p.Save(&state))
+	assert.NotContains(t, state.String(), "xyz")
+	assert.NotContains(t, state.String(), "s3cr3t-pa55")
+
+	password, port = "", 0
+	output := bytes.Buffer{}
+
+	r := newRedactFlow(bytes.NewBufferString("an0th3r-pa55\n20000\n"), &output, &password, &port, true)
+	assert.NoError(t, r.Resume(&state))
+	assert.Equal(t, "password", r.Current())
+
+	r.Run()
+
+	assert.Equal(t, "Give a password\n\nGive a port\n", output.String())
+	assert.Equal(t, "an0th3r-pa55", password)
+	assert.Equal(t, 20000, port)
+	assert.Len(t, r.Scenario(), 3)
+}
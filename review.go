@@ -0,0 +1,187 @@
+package strumt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SetReview defines whether Run shows accepted answers once the prompt
+// sequence is completed and lets the user pick one to change. Only the
+// chosen prompter is run again, prompters of a branch taken because
+// of the new answer are run as well. Answers of prompters no longer
+// on the path are removed. Prompters of groups and prompters
+// reading the environment can't be changed. Review needs a user
+// reading the writer, it has no effect when the sequence is driven
+// with Start and Submit. When reading user inputs fails during the
+// review, the sequence is aborted. Steps recorded during the review
+// are not saved by Save, so after Resume, Run shows the review again
+func (p *Prompts) SetReview(review bool) {
+	p.review = review
+}
+
+// reviewAnswers shows accepted answers until the user confirms them
+func (p *Prompts) reviewAnswers() {
+	p.reviewFrom = len(p.scenario)
+
+	for {
+		path, missing := p.path()
+
+		if missing != "" {
+			if !p.ask(missing) {
+				return
+			}
+
+			continue
+		}
+
+		steps := p.editable(path)
+		p.renderReview(steps)
+
+		input, err := readLine(p.reader, p.normalizers)
+
+		if err != nil {
+			p.abort(nil, err)
+			return
+		}
+
+		if input == "" {
+			p.prune(path)
+			return
+		}
+
+		i, err := strconv.Atoi(input)
+
+		if err != nil || i < 1 || i > len(steps) {
+			fmt.Fprintf(p.writer, "%s is not a valid choice\n", input)
+			continue
+		}
+
+		if !p.ask(steps[i-1].id) {
+			return
+		}
+	}
+}
+
+// path follows accepted answers from the first prompter, it returns
+// steps of the path and the key of the first prompter of the path
+// which has no accepted answer
+func (p *Prompts) path() ([]Step, string) {
+	accepted := map[string]Step{}
+
+	for _, step := range p.scenario {
+		if step.err == nil && !step.aborted && !step.help {
+			accepted[step.id] = step
+		}
+	}
+
+	path := []Step{}
+	seen := map[string]bool{}
+
+	for key := p.entry(p.first); key != "" && !seen[key]; {
+		step, ok := accepted[key]
+
+		if !ok {
+			return path, key
+		}
+
+		path = append(path, step)
+		seen[key] = true
+		key = step.next
+	}
+
+	return path, ""
+}
+
+// editable returns steps of the path the user can change
+func (p *Prompts) editable(path []Step) []Step {
+	steps := []Step{}
+
+	for _, step := range path {
+		if step.source != SourceEnv && !p.grouped(step.id) {
+			steps = append(steps, step)
+		}
+	}
+
+	return steps
+}
+
+// grouped returns true when key belongs to a group
+func (p *Prompts) grouped(key string) bool {
+	for moreKey := range p.groups {
+		id := strings.TrimSuffix(moreKey, groupMoreSuffix)
+
+		if key == moreKey || key == id+groupStartSuffix || strings.HasPrefix(key, id+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ask runs the prompter registered under key until its inputs
+// are accepted, it returns false when reading user inputs failed
+func (p *Prompts) ask(key string) bool {
+	from := len(p.scenario)
+	p.current = key
+	p.since = p.now()
+	state := p.state()
+
+	for !state.Done() && !p.accepted(key, from) {
+		renderPrompt(p.writer, state.Prompter(), p.promptString(state.Prompter()))
+
+		start := len(p.scenario)
+		inputs, source, err := p.read()
+
+		if err != nil {
+			p.abort(inputs, err)
+			return false
+		}
+
+		state = p.submit(inputs, source)
+		p.renderSteps(start)
+	}
+
+	p.current = ""
+
+	return true
+}
+
+// accepted returns true when inputs of the prompter registered
+// under key were accepted in a step recorded from the given index
+func (p *Prompts) accepted(key string, from int) bool {
+	for _, step := range p.scenario[from:] {
+		if step.id == key && step.err == nil && !step.help {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prune removes answers of prompters which are not on the path
+func (p *Prompts) prune(path []Step) {
+	keys := map[string]bool{}
+
+	for _, step := range path {
+		keys[step.id] = true
+	}
+
+	for key := range p.session.answers {
+		if !keys[key] {
+			delete(p.session.answers, key)
+		}
+	}
+}
+
+// renderReview renders steps preceded by the number to type to change them
+func (p *Prompts) renderReview(steps []Step) {
+	fmt.Fprintf(p.writer, "\nReview your answers :\n")
+
+	for i, step := range steps {
+		step = p.redact(step)
+		fmt.Fprintf(p.writer, "%d. %s : %s\n", i+1, step.prompt, strings.Join(step.inputs, ", "))
+	}
+
+	fmt.Fprintf(p.writer, "Type a number to change an answer or press enter to confirm\n")
+}
@@ -0,0 +1,183 @@
+package strumt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromptsRunWithReview(t *testing.T) {
+	type scenario struct {
+		name    string
+		input   string
+		answers map[string][]string
+		steps   []string
+		aborted bool
+	}
+
+	scenarios := []scenario{
+		{
+			"Answers confirmed",
+			"john\n31\n\n",
+			map[string][]string{"name": {"john"}, "age": {"31"}},
+			[]string{"name", "age"},
+			false,
+		},
+		{
+			"Aborted when reaching EOF",
+			"john\n31\n",
+			map[string][]string{"name": {"john"}, "age": {"31"}},
+			[]string{"name", "age", ""},
+			true,
+		},
+		{
+			"Answer changed",
+			"john\n31\n2\n32\n\n",
+			map[string][]string{"name": {"john"}, "age": {"32"}},
+			[]string{"name", "age", "age"},
+			false,
+		},
+		{
+			"Answer changed several times",
+			"john\n31\n2\n32\n2\n33\n\n",
+			map[string][]string{"name": {"john"}, "age": {"33"}},
+			[]string{"name", "age", "age", "age"},
+			false,
+		},
+		{
+			"Answer changed taking another branch",
+			"john\n31\n1\nadmin\nread\n\n",
+			map[string][]string{"name": {"admin"}, "rights": {"read"}, "age": {"31"}},
+			[]string{"name", "age", "name", "rights"},
+			false,
+		},
+		{
+			"Answer changed leaving a branch",
+			"admin\nread\n31\n1\njohn\n\n",
+			map[string][]string{"name": {"john"}, "age": {"31"}},
+			[]string{"name", "rights", "age", "name"},
+			false,
+		},
+		{
+			"Invalid choices",
+			"john\n31\n3\nwhatever\n0\n\n",
+			map[string][]string{"name": {"john"}, "age": {"31"}},
+			[]string{"name", "age"},
+			false,
+		},
+		{
+			"Aborted while changing an answer",
+			"john\n31\n1\n",
+			map[string][]string{"name": {"john"}, "age": {"31"}},
+			[]string{"name", "age", "name"},
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := newProgressFlow()
+			p.reader = bufio.NewReader(bytes.NewBufferString(s.input))
+			p.writer = ioutil.Discard
+			p.SetReview(true)
+			p.Run()
+
+			answers := map[string][]string{}
+
+			for id, answer := range p.Answers() {
+				answers[id] = answer.Inputs()
+			}
+
+			steps := []string{}
+
+			for _, step := range p.Scenario() {
+				steps = append(steps, step.ID())
+			}
+
+			assert.Equal(t, s.answers, answers)
+			assert.Equal(t, s.steps, steps)
+			assert.Equal(t, s.aborted, p.Scenario()[len(p.Scenario())-1].Aborted())
+		})
+	}
+}
+
+func TestPromptsRunWithReviewRendering(t *testing.T) {
+	var password string
+	var port int
+	var output bytes.Buffer
+
+	p := newRedactFlow(bytes.NewBufferString("user\nsecret123\n10000\n3\n10001\n\n"), &output, &password, &port, true)
+	p.SetReview(true)
+	p.Run()
+
+	review := "\nReview your answers :\n1. Give a username : user\n2. Give a password : ********\n3. Give a port : %s\nType a number to change an answer or press enter to confirm\n"
+
+	assert.Equal(t, "Give a username\n\nGive a password\n\nGive a port\n"+
+		fmt.Sprintf(review, "10000")+"Give a port\n"+fmt.Sprintf(review, "10001"), output.String())
+	assert.Equal(t, 10001, port)
+}
+
+func TestPromptsRunWithReviewAndGroup(t *testing.T) {
+	var output bytes.Buffer
+
+	connection := Connection{}
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("y\ndb1\n5432\nn\nuser\n\n"), &output)
+	p.AddGroup("databases", NewGroup(newConnectionFlow(&connection), 0, 0), "username")
+	p.AddLinePrompter(&StringPrompt{new(string), "Give a username", "username", "", "username"})
+	p.SetFirst("databases")
+	p.SetReview(true)
+	p.Run()
+
+	assert.Contains(t, output.String(), "Review your answers :\n1. Give a username : user\nType")
+	assert.Len(t, p.Answers(), 5)
+}
+
+func TestPromptsRunWithReviewEndingOnError(t *testing.T) {
+	var output bytes.Buffer
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("\n"), &output)
+	p.AddLinePrompter(&StringPrompt{new(string), "Give a username", "username", "", ""})
+	p.SetFirst("username")
+	p.SetReview(true)
+	p.Run()
+
+	assert.Equal(t, "Give a username\nEmpty value given\n", output.String())
+}
+
+func TestPromptsResumeAbortedReview(t *testing.T) {
+	p := newProgressFlow()
+	p.reader = bufio.NewReader(bytes.NewBufferString("john\n31\n2\n"))
+	p.writer = ioutil.Discard
+	p.SetReview(true)
+	p.Run()
+
+	assert.True(t, p.Scenario()[len(p.Scenario())-1].Aborted())
+
+	state := bytes.Buffer{}
+	assert.NoError(t, p.Save(&state))
+
+	var output bytes.Buffer
+
+	r := newProgressFlow()
+	r.reader = bufio.NewReader(bytes.NewBufferString("2\n32\n\n"))
+	r.writer = &output
+	r.SetReview(true)
+	assert.NoError(t, r.Resume(bytes.NewReader(state.Bytes())))
+	r.Run()
+
+	answers := map[string][]string{}
+
+	for id, answer := range r.Answers() {
+		answers[id] = answer.Inputs()
+	}
+
+	assert.Equal(t, map[string][]string{"name": {"john"}, "age": {"32"}}, answers)
+	assert.True(t, strings.HasPrefix(output.String(), "\nReview your answers :\n1. Give name : john\n2. Give age : 31\n"), output.String())
+	assert.False(t, r.Scenario()[len(r.Scenario())-1].Aborted())
+}
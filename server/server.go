@@ -0,0 +1,224 @@
+// Package server serves strumt prompt sequences to many concurrent
+// clients, each connection gets its own prompt sequence.
+//
+// Serve accepts connections from any net.Listener, e.g. a TCP listener
+// reachable with nc. ServeConn runs a single session on any
+// io.ReadWriteCloser, e.g. a channel of an SSH server.
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/antham/strumt/v2"
+)
+
+// ErrServerClosed is returned by Serve and ServeConn
+// once Close has been called
+var ErrServerClosed = errors.New("server closed")
+
+var errTooManyConnections = errors.New("too many connections")
+
+// Factory builds a fresh prompt sequence for a session,
+// most of the time using strumt.NewPromptsFromReaderAndWriter
+type Factory func(io.Reader, io.Writer) strumt.Prompts
+
+// Server runs a prompt sequence for each connection
+type Server struct {
+	factory        Factory
+	timeout        time.Duration
+	maxConnections int
+	onSessionEnd   func(*strumt.Prompts)
+	logger         *slog.Logger
+
+	mu        sync.Mutex
+	closed    bool
+	listeners map[net.Listener]struct{}
+	conns     map[io.ReadWriteCloser]struct{}
+	wg        sync.WaitGroup
+}
+
+// New creates a server building prompt sequences with factory
+func New(factory Factory) *Server {
+	return &Server{
+		factory:   factory,
+		listeners: map[net.Listener]struct{}{},
+		conns:     map[io.ReadWriteCloser]struct{}{},
+		logger:    slog.Default(),
+	}
+}
+
+// SetTimeout defines the maximum duration of a session,
+// the connection is closed once it's reached. A timeout of 0,
+// the default, means there is no limit
+func (s *Server) SetTimeout(timeout time.Duration) {
+	s.timeout = timeout
+}
+
+// SetMaxConnections defines how many sessions can run at the
+// same time, extra connections are refused. A limit of 0,
+// the default, means there is no limit
+func (s *Server) SetMaxConnections(max int) {
+	s.maxConnections = max
+}
+
+// OnSessionEnd defines a function called with the prompt sequence
+// of a session once it ended, to process the scenario or the answers
+func (s *Server) OnSessionEnd(f func(*strumt.Prompts)) {
+	s.onSessionEnd = f
+}
+
+// SetLogger defines the logger receiving panics recovered from
+// sessions, slog.Default is used by default
+func (s *Server) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// Serve accepts connections on listener and runs a session for
+// each of them in its own goroutine, it returns when the listener fails
+// or when the server is closed
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+
+	s.listeners[listener] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, listener)
+		s.mu.Unlock()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+
+			if closed {
+				return ErrServerClosed
+			}
+
+			return err
+		}
+
+		if err := s.track(conn); err != nil {
+			refuse(conn, err)
+			continue
+		}
+
+		go s.serve(conn)
+	}
+}
+
+// ServeConn runs a session on conn and closes it when the session ends
+func (s *Server) ServeConn(conn io.ReadWriteCloser) error {
+	if err := s.track(conn); err != nil {
+		refuse(conn, err)
+
+		return err
+	}
+
+	s.serve(conn)
+
+	return nil
+}
+
+// Close stops all listeners, closes all running sessions
+// and waits until they end
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+
+	var err error
+
+	for listener := range s.listeners {
+		if e := listener.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+
+	s.mu.Unlock()
+	s.wg.Wait()
+
+	return err
+}
+
+// track registers a connection, it returns an error
+// when the connection can't be accepted
+func (s *Server) track(conn io.ReadWriteCloser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrServerClosed
+	}
+
+	if s.maxConnections > 0 && len(s.conns) >= s.maxConnections {
+		return errTooManyConnections
+	}
+
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+
+	return nil
+}
+
+// refuse tells the client why its connection
+// can't be accepted and closes it
+func refuse(conn io.ReadWriteCloser, err error) {
+	if err == ErrServerClosed {
+		fmt.Fprintln(conn, "Server is shutting down")
+	} else {
+		fmt.Fprintln(conn, "Too many connections, try again later")
+	}
+
+	conn.Close()
+}
+
+func (s *Server) serve(conn io.ReadWriteCloser) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+	defer conn.Close()
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("session panicked", slog.Any("panic", r), slog.String("stack", string(debug.Stack())))
+		}
+	}()
+
+	if s.timeout > 0 {
+		timer := time.AfterFunc(s.timeout, func() {
+			conn.Close()
+		})
+
+		defer timer.Stop()
+	}
+
+	p := s.factory(conn, conn)
+	p.Run()
+
+	if s.onSessionEnd != nil {
+		s.onSessionEnd(&p)
+	}
+}
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/antham/strumt/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type StringPrompt struct {
+	store             *string
+	prompt            string
+	currentID         string
+	nextPrompt        string
+	nextPromptOnError string
+}
+
+func (s *StringPrompt) ID() string {
+	return s.currentID
+}
+
+func (s *StringPrompt) PromptString() string {
+	return s.prompt
+}
+
+func (s *StringPrompt) Parse(value string) error {
+	if value == "" {
+		return fmt.Errorf("Empty value given")
+	}
+
+	*(s.store) = value
+
+	return nil
+}
+
+func (s *StringPrompt) NextOnSuccess(value string) string {
+	return s.nextPrompt
+}
+
+func (s *StringPrompt) NextOnError(err error) string {
+	return s.nextPromptOnError
+}
+
+type PanicPrompt struct {
+	StringPrompt
+}
+
+func (p *PanicPrompt) Parse(value string) error {
+	if value == "panic" {
+		panic("unexpected input")
+	}
+
+	return p.StringPrompt.Parse(value)
+}
+
+func newServer() (*Server, *[]string, *sync.Mutex) {
+	var mu sync.Mutex
+	names := []string{}
+
+	s := New(func(reader io.Reader, writer io.Writer) strumt.Prompts {
+		p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+		p.AddLinePrompter(&StringPrompt{new(string), "Enter your name", "name", "", "name"})
+		p.SetFirst("name")
+
+		return p
+	})
+	s.OnSessionEnd(func(p *strumt.Prompts) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if answer, ok := p.Answers()["name"]; ok {
+			names = append(names, answer.Inputs()[0])
+		}
+	})
+
+	return s, &names, &mu
+}
+
+func listen(t *testing.T, s *Server) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	go s.Serve(listener)
+
+	return listener.Addr().String()
+}
+
+func TestServerServe(t *testing.T) {
+	s, names, mu := newServer()
+	addr := listen(t, s)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			conn, err := net.Dial("tcp", addr)
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			reader := bufio.NewReader(conn)
+
+			line, err := reader.ReadString('\n')
+			assert.NoError(t, err)
+			assert.Equal(t, "Enter your name\n", line)
+
+			fmt.Fprintf(conn, "\r\nuser%d\r\n", i)
+
+			output, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.Equal(t, "Empty value given\n\nEnter your name\n", string(output))
+		}(i)
+	}
+
+	wg.Wait()
+	assert.NoError(t, s.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.ElementsMatch(t, []string{"user0", "user1", "user2", "user3", "user4"}, *names)
+}
+
+func TestServerMaxConnections(t *testing.T) {
+	s, _, _ := newServer()
+	s.SetMaxConnections(1)
+	addr := listen(t, s)
+
+	first, err := net.Dial("tcp", addr)
+	assert.NoError(t, err)
+	defer first.Close()
+
+	line, err := bufio.NewReader(first).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "Enter your name\n", line)
+
+	second, err := net.Dial("tcp", addr)
+	assert.NoError(t, err)
+	defer second.Close()
+
+	output, err := io.ReadAll(second)
+	assert.NoError(t, err)
+	assert.Equal(t, "Too many connections, try again later\n", string(output))
+
+	assert.NoError(t, s.Close())
+}
+
+func TestServerTimeout(t *testing.T) {
+	s, names, mu := newServer()
+	s.SetTimeout(50 * time.Millisecond)
+	addr := listen(t, s)
+
+	conn, err := net.Dial("tcp", addr)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	start := time.Now()
+	output, err := io.ReadAll(conn)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Enter your name\n", string(output))
+	assert.True(t, time.Since(start) < time.Second)
+	assert.NoError(t, s.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Empty(t, *names)
+}
+
+func TestServerClose(t *testing.T) {
+	s, _, _ := newServer()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	served := make(chan error)
+
+	go func() {
+		served <- s.Serve(listener)
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	_, err = bufio.NewReader(conn).ReadString('\n')
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Close())
+	assert.Equal(t, ErrServerClosed, <-served)
+	assert.Equal(t, ErrServerClosed, s.Serve(listener))
+}
+
+func TestServerServeConn(t *testing.T) {
+	s, names, mu := newServer()
+
+	client, conn := net.Pipe()
+
+	go func() {
+		reader := bufio.NewReader(client)
+		reader.ReadString('\n')
+		fmt.Fprint(client, "user\n")
+		io.ReadAll(reader)
+	}()
+
+	assert.NoError(t, s.ServeConn(conn))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, []string{"user"}, *names)
+}
+
+func TestServerRecoversSessionPanics(t *testing.T) {
+	var logs bytes.Buffer
+
+	names := []string{}
+
+	s := New(func(reader io.Reader, writer io.Writer) strumt.Prompts {
+		p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+		p.AddLinePrompter(&PanicPrompt{StringPrompt{new(string), "Enter your name", "name", "", "name"}})
+		p.SetFirst("name")
+
+		return p
+	})
+	s.SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+	s.OnSessionEnd(func(p *strumt.Prompts) {
+		names = append(names, p.Answers()["name"].Inputs()[0])
+	})
+	addr := listen(t, s)
+
+	for _, input := range []string{"panic", "user"} {
+		conn, err := net.Dial("tcp", addr)
+		assert.NoError(t, err)
+
+		reader := bufio.NewReader(conn)
+
+		line, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "Enter your name\n", line)
+
+		fmt.Fprintf(conn, "%s\n", input)
+
+		_, err = io.ReadAll(reader)
+		assert.NoError(t, err)
+		conn.Close()
+	}
+
+	assert.NoError(t, s.Close())
+	assert.Equal(t, []string{"user"}, names)
+	assert.Contains(t, logs.String(), `level=ERROR msg="session panicked" panic="unexpected input"`)
+}
+
+func TestServerServeConnAfterClose(t *testing.T) {
+	s, _, _ := newServer()
+	assert.NoError(t, s.Close())
+
+	client, conn := net.Pipe()
+	output := make(chan string)
+
+	go func() {
+		data, _ := io.ReadAll(client)
+		output <- string(data)
+	}()
+
+	assert.Equal(t, ErrServerClosed, s.ServeConn(conn))
+	assert.Equal(t, "Server is shutting down\n", <-output)
+}
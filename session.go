@@ -0,0 +1,41 @@
+package strumt
+
+// Session holds datas shared by prompters during a prompt sequence,
+// it records inputs accepted by each prompter and lets prompters
+// store arbitrary values
+type Session struct {
+	answers map[string]Answer
+	values  map[string]interface{}
+}
+
+func newSession() *Session {
+	return &Session{answers: map[string]Answer{}, values: map[string]interface{}{}}
+}
+
+// Answer retrieves the last inputs accepted by the prompter referenced by id,
+// prompters of a subflow are referenced using "subflowID.prompterID"
+func (s *Session) Answer(id string) ([]string, bool) {
+	answer, ok := s.answers[id]
+
+	return answer.inputs, ok
+}
+
+// Set stores a value under the given key
+func (s *Session) Set(key string, value interface{}) {
+	s.values[key] = value
+}
+
+// Get retrieves a value stored under the given key
+func (s *Session) Get(key string) (interface{}, bool) {
+	value, ok := s.values[key]
+
+	return value, ok
+}
+
+// SessionValue retrieves a value stored under the given key in the session,
+// it returns false if there is no value or if the value has not the expected type
+func SessionValue[T any](s *Session, key string) (T, bool) {
+	value, ok := s.values[key].(T)
+
+	return value, ok
+}
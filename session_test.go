@@ -0,0 +1,145 @@
+package strumt
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type EnginePrompt struct {
+	session *Session
+}
+
+func (e *EnginePrompt) ID() string {
+	return "engine"
+}
+
+func (e *EnginePrompt) PromptString() string {
+	return "Give a database engine"
+}
+
+func (e *EnginePrompt) Parse(value string) error {
+	ports := map[string]int{"postgres": 5432, "mysql": 3306, "sqlite": 0}
+	port, ok := ports[value]
+
+	if !ok {
+		return fmt.Errorf("%s is not supported", value)
+	}
+
+	e.session.Set("defaultPort", port)
+
+	return nil
+}
+
+func (e *EnginePrompt) NextOnSuccess(value string) string {
+	return "port"
+}
+
+func (e *EnginePrompt) NextOnError(err error) string {
+	return "engine"
+}
+
+func (e *EnginePrompt) SetSession(s *Session) {
+	e.session = s
+}
+
+type PortPrompt struct {
+	session *Session
+	port    int
+}
+
+func (p *PortPrompt) ID() string {
+	return "port"
+}
+
+func (p *PortPrompt) PromptString() string {
+	return "Give a port"
+}
+
+func (p *PortPrompt) Parse(value string) error {
+	if port, ok := SessionValue[int](p.session, "defaultPort"); ok && value == "" {
+		p.port = port
+		return nil
+	}
+
+	return fmt.Errorf("custom port are not supported")
+}
+
+func (p *PortPrompt) NextOnSuccess(value string) string {
+	if engine, _ := p.session.Answer("engine"); engine[0] == "sqlite" {
+		return ""
+	}
+
+	return "host"
+}
+
+func (p *PortPrompt) NextOnError(err error) string {
+	return "port"
+}
+
+func (p *PortPrompt) SetSession(s *Session) {
+	p.session = s
+}
+
+func TestPromptsRunWithSession(t *testing.T) {
+	type scenario struct {
+		name    string
+		input   string
+		port    int
+		answers map[string]Answer
+	}
+
+	scenarios := []scenario{
+		{
+			"Branch on a previous answer",
+			"oracle\npostgres\n\nlocalhost\n",
+			5432,
+			map[string]Answer{"engine": {[]string{"postgres"}, nil}, "port": {[]string{""}, nil}, "host": {[]string{"localhost"}, nil}},
+		},
+		{
+			"Branch on a previous answer to end the sequence",
+			"sqlite\n\n",
+			0,
+			map[string]Answer{"engine": {[]string{"sqlite"}, nil}, "port": {[]string{""}, nil}},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			port := &PortPrompt{}
+
+			p := NewPromptsFromReaderAndWriter(bytes.NewBufferString(s.input), ioutil.Discard)
+			p.Session().Set("user", "admin")
+			p.AddLinePrompter(&EnginePrompt{})
+			p.AddLinePrompter(port)
+			p.AddLinePrompter(&StringPrompt{new(string), "Give a host", "host", "", "host"})
+			p.SetFirst("engine")
+			p.Run()
+
+			assert.Equal(t, s.port, port.port)
+			assert.Equal(t, s.answers, p.Answers())
+
+			user, ok := p.Session().Get("user")
+			assert.True(t, ok)
+			assert.Equal(t, "admin", user)
+		})
+	}
+}
+
+func TestSessionValue(t *testing.T) {
+	s := newSession()
+	s.Set("port", 5432)
+
+	port, ok := SessionValue[int](s, "port")
+	assert.True(t, ok)
+	assert.Equal(t, 5432, port)
+
+	_, ok = SessionValue[string](s, "port")
+	assert.False(t, ok)
+
+	_, ok = SessionValue[int](s, "host")
+	assert.False(t, ok)
+}
@@ -0,0 +1,279 @@
+// Package strumthttp exposes strumt prompt sequences as an HTTP JSON API,
+// so a web page can drive a wizard step by step with the same validation
+// and branching logic as the command line.
+//
+// The handler serves the following routes, relative to where it's mounted :
+//
+//	POST   /sessions       starts a new session and returns its state
+//	GET    /sessions/{id}  returns the state of a session
+//	POST   /sessions/{id}  submits inputs as {"inputs": ["..."]} and returns the new state
+//	DELETE /sessions/{id}  aborts a session
+package strumthttp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antham/strumt/v2"
+)
+
+// Kinds of prompters
+const (
+	KindLine      = "line"
+	KindMultiline = "multiline"
+)
+
+// DefaultTTL is the time an idle session is kept
+const DefaultTTL = 30 * time.Minute
+
+// maxBodySize is the maximum size of a submission
+const maxBodySize = 1 << 20
+
+// Factory builds a fresh prompt sequence for a session, most of the
+// time using strumt.NewPromptsFromReaderAndWriter. Sessions drive the
+// sequence with Start and Submit, so the reader and the writer given
+// to the factory are not used and the review enabled with
+// SetReview is never shown
+type Factory func(io.Reader, io.Writer) strumt.Prompts
+
+// State is the JSON representation of a session, when the prompter
+// is sensitive its inputs are redacted from errors and answers.
+// Help is the help text of the prompter when it has just been asked for
+type State struct {
+	Session   string              `json:"session"`
+	ID        string              `json:"id,omitempty"`
+	Prompt    string              `json:"prompt,omitempty"`
+	Kind      string              `json:"kind,omitempty"`
+	Choices   []string            `json:"choices,omitempty"`
+	Sensitive bool                `json:"sensitive,omitempty"`
+	Error     string              `json:"error,omitempty"`
+	Help      string              `json:"help,omitempty"`
+	Done      bool                `json:"done"`
+	Answers   map[string][]string `json:"answers,omitempty"`
+}
+
+type submission struct {
+	Inputs []string `json:"inputs"`
+}
+
+// Handler is an http.Handler managing prompt sequence sessions
+type Handler struct {
+	factory      Factory
+	ttl          time.Duration
+	onSessionEnd func(*strumt.Prompts)
+	maxSessions  int
+	mu           sync.Mutex
+	sessions     map[string]*session
+	lastExpire   time.Time
+	now          func() time.Time
+}
+
+// NewHandler creates a handler building prompt sequences with factory
+func NewHandler(factory Factory) *Handler {
+	return &Handler{factory: factory, ttl: DefaultTTL, sessions: map[string]*session{}, now: time.Now}
+}
+
+// SetTTL defines the time an idle session is kept before being aborted
+func (h *Handler) SetTTL(ttl time.Duration) {
+	h.ttl = ttl
+}
+
+// SetMaxSessions defines how many sessions can exist at the
+// same time, extra sessions are refused with a 503 status.
+// A limit of 0, the default, means there is no limit
+func (h *Handler) SetMaxSessions(max int) {
+	h.maxSessions = max
+}
+
+// OnSessionEnd defines a function called with the prompt sequence
+// of a session once it ended, sessions deleted or expired before
+// the end of their sequence are aborted
+func (h *Handler) OnSessionEnd(f func(*strumt.Prompts)) {
+	h.onSessionEnd = f
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.expire(false)
+
+	path := strings.Trim(r.URL.Path, "/")
+
+	switch {
+	case path == "sessions" && r.Method == http.MethodPost:
+		h.create(w)
+	case strings.HasPrefix(path, "sessions/") && strings.Count(path, "/") == 1:
+		s, ok := h.session(strings.TrimPrefix(path, "sessions/"))
+
+		if !ok {
+			writeError(w, http.StatusNotFound, "session not found")
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeState(w, http.StatusOK, s.state())
+		case http.MethodPost:
+			h.submit(w, r, s)
+		case http.MethodDelete:
+			h.remove(s)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (h *Handler) create(w http.ResponseWriter) {
+	id, err := newID()
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s := newSession(id, h.factory, h.onSessionEnd)
+	s.lastSeen = h.now()
+
+	if !h.add(s) {
+		h.expire(true)
+
+		if !h.add(s) {
+			writeError(w, http.StatusServiceUnavailable, "too many sessions, try again later")
+			return
+		}
+	}
+
+	s.start()
+
+	writeState(w, http.StatusCreated, s.state())
+}
+
+func (h *Handler) submit(w http.ResponseWriter, r *http.Request, s *session) {
+	sub := submission{}
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&sub); err != nil {
+		var tooLarge *http.MaxBytesError
+
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("body is larger than %d bytes", maxBodySize))
+			return
+		}
+
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body : %s", err))
+		return
+	}
+
+	state, err := s.submit(sub.Inputs)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeState(w, http.StatusOK, state)
+}
+
+// add registers a session, it returns false
+// when the limit of sessions is reached
+func (h *Handler) add(s *session) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.maxSessions > 0 && len(h.sessions) >= h.maxSessions {
+		return false
+	}
+
+	h.sessions[s.id] = s
+
+	return true
+}
+
+// session returns the session referenced by id,
+// a session idle for too long is aborted
+func (h *Handler) session(id string) (*session, bool) {
+	h.mu.Lock()
+	s, ok := h.sessions[id]
+
+	if ok && h.now().Sub(s.lastSeen) > h.ttl {
+		delete(h.sessions, id)
+		h.mu.Unlock()
+		s.close()
+
+		return nil, false
+	}
+
+	if ok {
+		s.lastSeen = h.now()
+	}
+
+	h.mu.Unlock()
+
+	return s, ok
+}
+
+func (h *Handler) remove(s *session) {
+	h.mu.Lock()
+	delete(h.sessions, s.id)
+	h.mu.Unlock()
+
+	s.close()
+}
+
+// expire aborts sessions idle for too long, sessions are walked
+// at most once per TTL unless force is true
+func (h *Handler) expire(force bool) {
+	h.mu.Lock()
+
+	if !force && h.now().Sub(h.lastExpire) < h.ttl {
+		h.mu.Unlock()
+		return
+	}
+
+	h.lastExpire = h.now()
+	expired := []*session{}
+
+	for id, s := range h.sessions {
+		if h.now().Sub(s.lastSeen) > h.ttl {
+			expired = append(expired, s)
+			delete(h.sessions, id)
+		}
+	}
+
+	h.mu.Unlock()
+
+	for _, s := range expired {
+		s.close()
+	}
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func writeState(w http.ResponseWriter, status int, state State) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(state)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
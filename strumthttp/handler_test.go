@@ -0,0 +1,409 @@
+package strumthttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antham/strumt/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type IntPrompt struct {
+	store *int
+}
+
+func (i *IntPrompt) ID() string {
+	return "age"
+}
+
+func (i *IntPrompt) PromptString() string {
+	return "Enter your age"
+}
+
+func (i *IntPrompt) Parse(value string) error {
+	age, err := strconv.Atoi(value)
+
+	if err != nil {
+		return fmt.Errorf("%s is not a valid number", value)
+	}
+
+	*(i.store) = age
+
+	return nil
+}
+
+func (i *IntPrompt) NextOnSuccess(value string) string {
+	return "shell"
+}
+
+func (i *IntPrompt) NextOnError(err error) string {
+	return "age"
+}
+
+type ShellPrompt struct{}
+
+func (s *ShellPrompt) ID() string {
+	return "shell"
+}
+
+func (s *ShellPrompt) PromptString() string {
+	return "Choose a shell"
+}
+
+func (s *ShellPrompt) Parse(value string) error {
+	for _, choice := range s.Choices() {
+		if value == choice {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is not a supported shell", value)
+}
+
+func (s *ShellPrompt) NextOnSuccess(value string) string {
+	return "hosts"
+}
+
+func (s *ShellPrompt) NextOnError(err error) string {
+	return "shell"
+}
+
+func (s *ShellPrompt) Choices() []string {
+	return []string{"bash", "zsh"}
+}
+
+type HostsPrompt struct{}
+
+func (h *HostsPrompt) ID() string {
+	return "hosts"
+}
+
+func (h *HostsPrompt) PromptString() string {
+	return "Give some hosts"
+}
+
+func (h *HostsPrompt) Parse(values []string) error {
+	return nil
+}
+
+func (h *HostsPrompt) NextOnSuccess(values []string) string {
+	return ""
+}
+
+func (h *HostsPrompt) NextOnError(err error) string {
+	return "hosts"
+}
+
+func (h *HostsPrompt) Terminator() string {
+	return "."
+}
+
+type NamesPrompt struct {
+	store *[]string
+}
+
+func (n *NamesPrompt) ID() string {
+	return "names"
+}
+
+func (n *NamesPrompt) PromptString() string {
+	return "Give some names"
+}
+
+func (n *NamesPrompt) Parse(values []string) error {
+	*(n.store) = values
+
+	return nil
+}
+
+func (n *NamesPrompt) NextOnSuccess(values []string) string {
+	return "age"
+}
+
+func (n *NamesPrompt) NextOnError(err error) string {
+	return "names"
+}
+
+func newHandler() *Handler {
+	return NewHandler(func(reader io.Reader, writer io.Writer) strumt.Prompts {
+		p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+		p.AddLinePrompter(&IntPrompt{new(int)})
+		p.AddLinePrompter(&ShellPrompt{})
+		p.AddMultilinePrompter(&HostsPrompt{})
+		p.SetFirst("age")
+
+		return p
+	})
+}
+
+func request(t *testing.T, h http.Handler, method string, path string, body string) (int, State) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+
+	state := State{}
+
+	if w.Code < 300 && w.Body.Len() > 0 {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &state))
+	}
+
+	return w.Code, state
+}
+
+func TestHandler(t *testing.T) {
+	h := newHandler()
+
+	code, state := request(t, h, http.MethodPost, "/sessions", "")
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Len(t, state.Session, 32)
+
+	session := state.Session
+	path := "/sessions/" + session
+
+	code, state = request(t, h, http.MethodGet, path, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, State{Session: session, ID: "age", Prompt: "Enter your age", Kind: KindLine}, state)
+
+	code, state = request(t, h, http.MethodPost, path, `{"inputs":["whatever"]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, State{Session: session, ID: "age", Prompt: "Enter your age", Kind: KindLine, Error: "whatever is not a valid number"}, state)
+
+	code, state = request(t, h, http.MethodPost, path, `{"inputs":["31"]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, State{Session: session, ID: "shell", Prompt: "Choose a shell", Kind: KindLine, Choices: []string{"bash", "zsh"}}, state)
+
+	code, state = request(t, h, http.MethodPost, path, `{"inputs":["zsh"]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, State{Session: session, ID: "hosts", Prompt: "Give some hosts", Kind: KindMultiline}, state)
+
+	code, state = request(t, h, http.MethodPost, path, `{"inputs":["host1", "", "host2"]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, State{Session: session, Done: true, Answers: map[string][]string{"age": {"31"}, "shell": {"zsh"}, "hosts": {"host1", "", "host2"}}}, state)
+
+	code, _ = request(t, h, http.MethodPost, path, `{"inputs":["test"]}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = request(t, h, http.MethodDelete, path, "")
+	assert.Equal(t, http.StatusNoContent, code)
+
+	code, _ = request(t, h, http.MethodGet, path, "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestHandlerErrors(t *testing.T) {
+	h := newHandler()
+
+	_, state := request(t, h, http.MethodPost, "/sessions", "")
+	path := "/sessions/" + state.Session
+
+	type scenario struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+	}
+
+	scenarios := []scenario{
+		{"Unknown route", http.MethodGet, "/whatever", "", http.StatusNotFound},
+		{"Unknown session", http.MethodGet, "/sessions/whatever", "", http.StatusNotFound},
+		{"Method not allowed", http.MethodPut, path, "", http.StatusMethodNotAllowed},
+		{"Invalid body", http.MethodPost, path, "{", http.StatusBadRequest},
+		{"Several inputs for a line prompter", http.MethodPost, path, `{"inputs":["1","2"]}`, http.StatusBadRequest},
+		{"Input with new lines", http.MethodPost, path, `{"inputs":["1\n2"]}`, http.StatusBadRequest},
+		{"Body too large", http.MethodPost, path, `{"inputs":["` + strings.Repeat("1", maxBodySize) + `"]}`, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			code, _ := request(t, h, s.method, s.path, s.body)
+			assert.Equal(t, s.code, code)
+		})
+	}
+}
+
+func TestHandlerExpiresIdleSessions(t *testing.T) {
+	h := newHandler()
+	h.SetTTL(time.Minute)
+
+	ended := make(chan []strumt.Step, 1)
+	h.OnSessionEnd(func(p *strumt.Prompts) {
+		ended <- p.Scenario()
+	})
+
+	now := time.Now()
+	h.now = func() time.Time { return now }
+
+	_, state := request(t, h, http.MethodPost, "/sessions", "")
+	path := "/sessions/" + state.Session
+
+	now = now.Add(30 * time.Second)
+	code, _ := request(t, h, http.MethodGet, path, "")
+	assert.Equal(t, http.StatusOK, code)
+
+	now = now.Add(2 * time.Minute)
+	code, _ = request(t, h, http.MethodGet, path, "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	scenario := <-ended
+	assert.Len(t, scenario, 1)
+	assert.Equal(t, "age", scenario[0].ID())
+	assert.Equal(t, io.EOF, scenario[0].Error())
+}
+
+func TestHandlerMaxSessions(t *testing.T) {
+	h := newHandler()
+	h.SetTTL(time.Minute)
+	h.SetMaxSessions(1)
+
+	now := time.Now()
+	h.now = func() time.Time { return now }
+
+	code, state := request(t, h, http.MethodPost, "/sessions", "")
+	assert.Equal(t, http.StatusCreated, code)
+
+	code, _ = request(t, h, http.MethodPost, "/sessions", "")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	code, _ = request(t, h, http.MethodDelete, "/sessions/"+state.Session, "")
+	assert.Equal(t, http.StatusNoContent, code)
+
+	code, _ = request(t, h, http.MethodPost, "/sessions", "")
+	assert.Equal(t, http.StatusCreated, code)
+
+	now = now.Add(30 * time.Second)
+	code, _ = request(t, h, http.MethodPost, "/sessions", "")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	now = now.Add(2 * time.Minute)
+	code, _ = request(t, h, http.MethodPost, "/sessions", "")
+	assert.Equal(t, http.StatusCreated, code)
+}
+
+type TokenPrompt struct{}
+
+func (t *TokenPrompt) ID() string {
+	return "token"
+}
+
+func (t *TokenPrompt) PromptString() string {
+	return "Give a token"
+}
+
+func (t *TokenPrompt) Parse(value string) error {
+	if len(value) < 8 {
+		return fmt.Errorf("%s is too short", value)
+	}
+
+	return nil
+}
+
+func (t *TokenPrompt) NextOnSuccess(value string) string {
+	return ""
+}
+
+func (t *TokenPrompt) NextOnError(err error) string {
+	return "token"
+}
+
+func (t *TokenPrompt) Sensitive() bool {
+	return true
+}
+
+func (t *TokenPrompt) Help() string {
+	return "The token is shown in your account settings"
+}
+
+func TestHandlerRedactsSensitiveInputs(t *testing.T) {
+	h := NewHandler(func(reader io.Reader, writer io.Writer) strumt.Prompts {
+		p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+		p.AddLinePrompter(&TokenPrompt{})
+		p.SetFirst("token")
+
+		return p
+	})
+
+	_, state := request(t, h, http.MethodPost, "/sessions", "")
+	session := state.Session
+	path := "/sessions/" + session
+	assert.Equal(t, State{Session: session, ID: "token", Prompt: "Give a token", Kind: KindLine, Sensitive: true}, state)
+
+	_, state = request(t, h, http.MethodPost, path, `{"inputs":["abc"]}`)
+	assert.Equal(t, State{Session: session, ID: "token", Prompt: "Give a token", Kind: KindLine, Sensitive: true, Error: "******** is too short"}, state)
+
+	_, state = request(t, h, http.MethodPost, path, `{"inputs":["s3cr3t-t0k3n"]}`)
+	assert.Equal(t, State{Session: session, Done: true, Answers: map[string][]string{"token": {strumt.Redacted}}}, state)
+}
+
+func TestHandlerShowsHelp(t *testing.T) {
+	h := NewHandler(func(reader io.Reader, writer io.Writer) strumt.Prompts {
+		p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+		p.AddLinePrompter(&TokenPrompt{})
+		p.SetFirst("token")
+
+		return p
+	})
+
+	_, state := request(t, h, http.MethodPost, "/sessions", "")
+	session := state.Session
+	path := "/sessions/" + session
+
+	_, state = request(t, h, http.MethodPost, path, `{"inputs":["?"]}`)
+	assert.Equal(t, State{Session: session, ID: "token", Prompt: "Give a token", Kind: KindLine, Sensitive: true, Help: "The token is shown in your account settings"}, state)
+
+	_, state = request(t, h, http.MethodPost, path, `{"inputs":["abc"]}`)
+	assert.Equal(t, State{Session: session, ID: "token", Prompt: "Give a token", Kind: KindLine, Sensitive: true, Error: "******** is too short"}, state)
+}
+
+func TestHandlerKeepsMultilineInputsTogether(t *testing.T) {
+	var names []string
+	var age int
+
+	h := NewHandler(func(reader io.Reader, writer io.Writer) strumt.Prompts {
+		p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+		p.AddMultilinePrompter(&NamesPrompt{&names})
+		p.AddLinePrompter(&IntPrompt{&age})
+		p.SetFirst("names")
+
+		return p
+	})
+
+	_, state := request(t, h, http.MethodPost, "/sessions", "")
+	session := state.Session
+
+	_, state = request(t, h, http.MethodPost, "/sessions/"+session, `{"inputs":["a","","31"]}`)
+	assert.Equal(t, State{Session: session, ID: "age", Prompt: "Enter your age", Kind: KindLine}, state)
+	assert.Equal(t, []string{"a", "", "31"}, names)
+	assert.Equal(t, 0, age)
+}
+
+func TestHandlerIgnoresReview(t *testing.T) {
+	h := NewHandler(func(reader io.Reader, writer io.Writer) strumt.Prompts {
+		p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+		p.AddLinePrompter(&IntPrompt{new(int)})
+		p.AddLinePrompter(&ShellPrompt{})
+		p.SetFirst("age")
+		p.SetReview(true)
+
+		return p
+	})
+
+	_, state := request(t, h, http.MethodPost, "/sessions", "")
+	path := "/sessions/" + state.Session
+
+	request(t, h, http.MethodPost, path, `{"inputs":["31"]}`)
+	code, state := request(t, h, http.MethodPost, path, `{"inputs":["bash"]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, state.Done)
+
+	code, state = request(t, h, http.MethodGet, path, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string][]string{"age": {"31"}, "shell": {"bash"}}, state.Answers)
+}
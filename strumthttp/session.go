@@ -0,0 +1,149 @@
+package strumthttp
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antham/strumt/v2"
+)
+
+// session drives a prompt sequence with inputs
+// submitted through the API
+type session struct {
+	id       string
+	lastSeen time.Time
+
+	mu      sync.Mutex
+	prompts strumt.Prompts
+	current strumt.State
+	onEnd   func(*strumt.Prompts)
+	ended   bool
+}
+
+func newSession(id string, factory Factory, onEnd func(*strumt.Prompts)) *session {
+	s := &session{id: id, onEnd: onEnd}
+	s.prompts = factory(strings.NewReader(""), io.Discard)
+
+	return s
+}
+
+func (s *session) start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.current = s.prompts.Start()
+	s.end()
+}
+
+// end calls onEnd once the prompt sequence is done
+func (s *session) end() {
+	if !s.current.Done() || s.ended {
+		return
+	}
+
+	s.ended = true
+
+	if s.onEnd != nil {
+		s.onEnd(&s.prompts)
+	}
+}
+
+func (s *session) submit(inputs []string) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current.Done() {
+		return State{}, fmt.Errorf("session has ended")
+	}
+
+	for _, input := range inputs {
+		if strings.ContainsAny(input, "\r\n") {
+			return State{}, fmt.Errorf("an input can't contain new lines")
+		}
+	}
+
+	if _, ok := s.current.Prompter().(strumt.LinePrompter); ok && len(inputs) != 1 {
+		return State{}, fmt.Errorf("a single input is expected")
+	}
+
+	s.current = s.prompts.Submit(inputs)
+	s.end()
+
+	return s.unsafeState(), nil
+}
+
+func (s *session) state() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.unsafeState()
+}
+
+// unsafeState must be called while holding the session lock
+func (s *session) unsafeState() State {
+	state := State{Session: s.id}
+
+	if s.current.Done() {
+		state.Done = true
+		state.Answers = map[string][]string{}
+
+		for id, answer := range s.prompts.Answers() {
+			state.Answers[id] = answer.Inputs()
+
+			if prompt, ok := s.prompts.Prompter(id); ok && isSensitive(prompt) {
+				state.Answers[id] = []string{strumt.Redacted}
+			}
+		}
+
+		if s.current.Error() != nil {
+			state.Error = s.current.Error().Error()
+		}
+
+		return state
+	}
+
+	prompt := s.current.Prompter()
+	state.ID = s.current.ID()
+	state.Prompt = prompt.PromptString()
+	state.Kind = KindLine
+	state.Help = s.current.Help()
+
+	if _, ok := prompt.(strumt.MultilinePrompter); ok {
+		state.Kind = KindMultiline
+	}
+
+	if c, ok := prompt.(strumt.ChoicePrompter); ok {
+		state.Choices = c.Choices()
+	}
+
+	state.Sensitive = isSensitive(prompt)
+
+	if s.current.Error() != nil {
+		state.Error = s.current.Error().Error()
+	}
+
+	return state
+}
+
+func isSensitive(prompt strumt.Prompter) bool {
+	s, ok := prompt.(strumt.Sensitive)
+
+	return ok && s.Sensitive()
+}
+
+// close aborts the prompt sequence if it's still running
+func (s *session) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current.Done() {
+		return
+	}
+
+	s.prompts.Abort(io.EOF)
+	s.current = strumt.State{}
+	s.end()
+}
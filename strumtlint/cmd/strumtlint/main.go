@@ -0,0 +1,14 @@
+// Command strumtlint reports strumt prompter IDs which are referenced but
+// never registered. It can be run on its own or through go vet :
+//
+//	go vet -vettool=$(which strumtlint) ./...
+package main
+
+import (
+	"github.com/antham/strumt/v2/strumtlint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(strumtlint.Analyzer)
+}
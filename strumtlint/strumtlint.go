@@ -0,0 +1,463 @@
+// Package strumtlint defines an analyzer reporting prompter IDs which are
+// referenced but never registered, such a typo silently ends the prompt
+// sequence at runtime.
+//
+// IDs are string constants returned by ID, NextOnSuccess and NextOnError
+// methods of registered prompters or given to SetFirst, AddSubflow and
+// AddGroup. When a method returns a field of its receiver, the value is
+// taken from the composite literal given to AddLinePrompter or
+// AddMultilinePrompter. A package registering a prompter whose ID can't be
+// resolved statically is not checked to avoid false positives.
+package strumtlint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const strumtPath = "github.com/antham/strumt/v2"
+
+// Analyzer reports prompter IDs referenced but never registered
+var Analyzer = &analysis.Analyzer{
+	Name:      "strumtlint",
+	Doc:       "report strumt prompter IDs which are referenced but never registered",
+	Run:       run,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(returnsFact)},
+}
+
+// returnsFact records what an ID, NextOnSuccess or NextOnError method
+// returns, so prompters defined in another package can be checked
+type returnsFact struct {
+	Values  []string
+	Fields  []string
+	Dynamic bool
+}
+
+func (*returnsFact) AFact() {}
+
+func (r *returnsFact) String() string {
+	returns := []string{}
+
+	for _, value := range r.Values {
+		returns = append(returns, fmt.Sprintf("%q", value))
+	}
+
+	for _, field := range r.Fields {
+		returns = append(returns, "."+field)
+	}
+
+	if r.Dynamic {
+		returns = append(returns, "?")
+	}
+
+	return "returns(" + strings.Join(returns, " ") + ")"
+}
+
+// reference is a prompter ID used at a given position
+type reference struct {
+	id  string
+	pos token.Pos
+}
+
+type checker struct {
+	pass       *analysis.Pass
+	returns    map[*types.Func]*returnsFact
+	positions  map[*types.Func]map[string]token.Pos
+	registered map[string]bool
+	references []reference
+	dynamic    bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	if pass.Pkg.Path() == strumtPath {
+		return nil, nil
+	}
+
+	c := &checker{
+		pass:       pass,
+		returns:    map[*types.Func]*returnsFact{},
+		positions:  map[*types.Func]map[string]token.Pos{},
+		registered: map[string]bool{},
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		c.method(n.(*ast.FuncDecl))
+	})
+
+	registrations := 0
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		if c.call(n.(*ast.CallExpr)) {
+			registrations++
+		}
+	})
+
+	if registrations == 0 || c.dynamic {
+		return nil, nil
+	}
+
+	reported := map[token.Pos]bool{}
+
+	for _, ref := range c.references {
+		if ref.id == "" || c.registered[ref.id] || reported[ref.pos] {
+			continue
+		}
+
+		reported[ref.pos] = true
+		pass.Reportf(ref.pos, "prompter ID %q is referenced but never registered%s", ref.id, suggest(ref.id, c.registered))
+	}
+
+	return nil, nil
+}
+
+// method records values returned by a method defining prompter IDs
+func (c *checker) method(decl *ast.FuncDecl) {
+	switch decl.Name.Name {
+	case "ID", "NextOnSuccess", "NextOnError":
+	default:
+		return
+	}
+
+	fn, ok := c.pass.TypesInfo.Defs[decl.Name].(*types.Func)
+
+	if !ok || decl.Recv == nil || decl.Body == nil {
+		return
+	}
+
+	results := fn.Type().(*types.Signature).Results()
+
+	if results.Len() != 1 || !types.Identical(results.At(0).Type(), types.Typ[types.String]) {
+		return
+	}
+
+	var recv types.Object
+
+	if names := decl.Recv.List[0].Names; len(names) > 0 {
+		recv = c.pass.TypesInfo.Defs[names[0]]
+	}
+
+	fact := &returnsFact{}
+	positions := map[string]token.Pos{}
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) != 1 {
+				fact.Dynamic = true
+				return true
+			}
+
+			if value, ok := c.constant(n.Results[0]); ok {
+				fact.Values = append(fact.Values, value)
+				positions[value] = n.Results[0].Pos()
+				return true
+			}
+
+			if sel, ok := n.Results[0].(*ast.SelectorExpr); ok && recv != nil {
+				if x, ok := sel.X.(*ast.Ident); ok && c.pass.TypesInfo.Uses[x] == recv {
+					fact.Fields = append(fact.Fields, sel.Sel.Name)
+					return true
+				}
+			}
+
+			fact.Dynamic = true
+		}
+
+		return true
+	})
+
+	c.returns[fn] = fact
+	c.positions[fn] = positions
+	c.pass.ExportObjectFact(fn, fact)
+}
+
+func (c *checker) constant(expr ast.Expr) (string, bool) {
+	tv, ok := c.pass.TypesInfo.Types[expr]
+
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(tv.Value), true
+}
+
+// call records IDs registered or referenced by a method call
+// on strumt.Prompts, it returns true when a prompter is registered
+func (c *checker) call(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+
+	if !ok {
+		return false
+	}
+
+	fn, ok := c.pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != strumtPath || !isPromptsMethod(fn) {
+		return false
+	}
+
+	switch fn.Name() {
+	case "AddLinePrompter", "AddMultilinePrompter":
+		c.prompter(call, call.Args[0])
+		return true
+	case "SetFirst":
+		c.reference(call.Args[0])
+	case "AddSubflow", "AddGroup":
+		if id, ok := c.constant(call.Args[0]); ok {
+			c.registered[id] = true
+		} else {
+			c.dynamic = true
+		}
+
+		c.reference(call.Args[2])
+		return true
+	}
+
+	return false
+}
+
+func isPromptsMethod(fn *types.Func) bool {
+	recv := fn.Type().(*types.Signature).Recv()
+
+	if recv == nil {
+		return false
+	}
+
+	if ptr, ok := recv.Type().(*types.Pointer); ok {
+		named, ok := ptr.Elem().(*types.Named)
+
+		return ok && named.Obj().Name() == "Prompts"
+	}
+
+	return false
+}
+
+func (c *checker) reference(expr ast.Expr) {
+	if id, ok := c.constant(expr); ok {
+		c.references = append(c.references, reference{id, expr.Pos()})
+	}
+}
+
+// prompter records the ID of a registered prompter and IDs it references
+func (c *checker) prompter(call *ast.CallExpr, arg ast.Expr) {
+	typ := c.pass.TypesInfo.TypeOf(arg)
+
+	for _, name := range []string{"ID", "NextOnSuccess", "NextOnError"} {
+		obj, index, _ := types.LookupFieldOrMethod(typ, true, nil, name)
+		fn, ok := obj.(*types.Func)
+
+		if !ok {
+			continue
+		}
+
+		// a method promoted from an embedded struct
+		// reads fields of the embedded literal
+		lit := compositeLit(arg)
+
+		for _, i := range index[:len(index)-1] {
+			lit = c.embedded(lit, i)
+		}
+
+		fact, positions := c.returnsOf(fn)
+
+		if fact == nil {
+			if name == "ID" {
+				c.dynamic = true
+			}
+
+			continue
+		}
+
+		ids := []reference{}
+
+		for _, value := range fact.Values {
+			pos, ok := positions[value]
+
+			if !ok {
+				pos = call.Pos()
+			}
+
+			ids = append(ids, reference{value, pos})
+		}
+
+		for _, field := range fact.Fields {
+			if value, pos, ok := c.field(lit, field); ok {
+				ids = append(ids, reference{value, pos})
+			} else if name == "ID" {
+				c.dynamic = true
+			}
+		}
+
+		if fact.Dynamic && name == "ID" {
+			c.dynamic = true
+		}
+
+		for _, id := range ids {
+			if name == "ID" {
+				c.registered[id.id] = true
+			} else {
+				c.references = append(c.references, id)
+			}
+		}
+	}
+}
+
+func (c *checker) returnsOf(fn *types.Func) (*returnsFact, map[string]token.Pos) {
+	if fact, ok := c.returns[fn]; ok {
+		return fact, c.positions[fn]
+	}
+
+	fact := &returnsFact{}
+
+	if c.pass.ImportObjectFact(fn, fact) {
+		return fact, nil
+	}
+
+	return nil, nil
+}
+
+// field returns the constant string given to a field of a composite literal
+func (c *checker) field(lit *ast.CompositeLit, name string) (string, token.Pos, bool) {
+	if lit == nil {
+		return "", token.NoPos, false
+	}
+
+	expr, ok := c.element(lit, name)
+
+	// a field which is not set is an empty string
+	if !ok {
+		return "", lit.Pos(), true
+	}
+
+	if value, ok := c.constant(expr); ok {
+		return value, expr.Pos(), true
+	}
+
+	return "", token.NoPos, false
+}
+
+// embedded returns the composite literal given to the
+// embedded field at index i of a composite literal
+func (c *checker) embedded(lit *ast.CompositeLit, i int) *ast.CompositeLit {
+	if lit == nil {
+		return nil
+	}
+
+	st, ok := c.pass.TypesInfo.TypeOf(lit).Underlying().(*types.Struct)
+
+	if !ok || i >= st.NumFields() {
+		return nil
+	}
+
+	if expr, ok := c.element(lit, st.Field(i).Name()); ok {
+		return compositeLit(expr)
+	}
+
+	return nil
+}
+
+// element returns the value given to a field of a composite literal
+func (c *checker) element(lit *ast.CompositeLit, name string) (ast.Expr, bool) {
+	st, ok := c.pass.TypesInfo.TypeOf(lit).Underlying().(*types.Struct)
+
+	if !ok {
+		return nil, false
+	}
+
+	for i, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == name {
+				return kv.Value, true
+			}
+
+			continue
+		}
+
+		if i < st.NumFields() && st.Field(i).Name() == name {
+			return elt, true
+		}
+	}
+
+	return nil, false
+}
+
+func compositeLit(expr ast.Expr) *ast.CompositeLit {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return compositeLit(e.X)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return compositeLit(e.X)
+		}
+	case *ast.CompositeLit:
+		return e
+	}
+
+	return nil
+}
+
+// suggest returns a hint with the closest registered ID
+func suggest(id string, registered map[string]bool) string {
+	ids := []string{}
+
+	for r := range registered {
+		ids = append(ids, r)
+	}
+
+	sort.Strings(ids)
+
+	best, distance := "", len(id)/2+1
+
+	for _, r := range ids {
+		if d := levenshtein(id, r); d < distance {
+			best, distance = r, d
+		}
+	}
+
+	if best == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(", did you mean %q ?", best)
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous = current
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,77 @@
+package a
+
+import (
+	"errors"
+
+	"github.com/antham/strumt/v2"
+)
+
+const idAge = "age"
+
+type NamePrompt struct{}
+
+func (n *NamePrompt) ID() string { return "name" } // want ID:`returns\("name"\)`
+
+func (n *NamePrompt) PromptString() string { return "Enter your name" }
+
+func (n *NamePrompt) Parse(value string) error { return nil }
+
+func (n *NamePrompt) NextOnSuccess(value string) string { // want NextOnSuccess:`returns\("rights" "age"\)`
+	if value == "admin" {
+		return "rights" // want `prompter ID "rights" is referenced but never registered`
+	}
+
+	return idAge
+}
+
+func (n *NamePrompt) NextOnError(err error) string { return "nmae" } // want `prompter ID "nmae" is referenced but never registered, did you mean "name" \?` NextOnError:`returns\("nmae"\)`
+
+type FieldPrompt struct {
+	store      *string
+	prompt     string
+	id         string
+	next       string
+	nextOnFail string
+}
+
+func (f *FieldPrompt) ID() string { return f.id } // want ID:`returns\(\.id\)`
+
+func (f *FieldPrompt) PromptString() string { return f.prompt }
+
+func (f *FieldPrompt) Parse(value string) error { return errors.New("invalid") }
+
+func (f *FieldPrompt) NextOnSuccess(value string) string { return f.next } // want NextOnSuccess:`returns\(\.next\)`
+
+func (f *FieldPrompt) NextOnError(err error) string { return f.nextOnFail } // want NextOnError:`returns\(\.nextOnFail\)`
+
+type PinPrompt struct {
+	FieldPrompt
+}
+
+type HostsPrompt struct{}
+
+func (h *HostsPrompt) ID() string { return "hosts" } // want ID:`returns\("hosts"\)`
+
+func (h *HostsPrompt) PromptString() string { return "Give some hosts" }
+
+func (h *HostsPrompt) Parse(values []string) error { return nil }
+
+func (h *HostsPrompt) NextOnSuccess(values []string) string { return "" } // want NextOnSuccess:`returns\(""\)`
+
+func (h *HostsPrompt) NextOnError(err error) string { return "hosts" } // want NextOnError:`returns\("hosts"\)`
+
+func newPrompts() strumt.Prompts {
+	p := strumt.NewPromptsFromReaderAndWriter(nil, nil)
+	p.AddLinePrompter(&NamePrompt{})
+	p.AddLinePrompter(&FieldPrompt{new(string), "Enter your age", idAge, "shell", idAge})
+	p.AddLinePrompter(&FieldPrompt{id: "shell", next: "hots", nextOnFail: "shell"}) // want `prompter ID "hots" is referenced but never registered, did you mean "hosts" \?`
+	p.AddLinePrompter(&FieldPrompt{id: "confirm"})
+	p.AddLinePrompter(&PinPrompt{FieldPrompt{id: "pin", next: "confirm"}})
+	p.AddLinePrompter(&PinPrompt{FieldPrompt: FieldPrompt{id: "code", next: "cnfirm"}}) // want `prompter ID "cnfirm" is referenced but never registered, did you mean "confirm" \?`
+	p.AddMultilinePrompter(&HostsPrompt{})
+	p.AddSubflow("db", &strumt.Prompts{}, "confirm")
+	p.AddGroup("servers", &strumt.Group{}, "summary") // want `prompter ID "summary" is referenced but never registered`
+	p.SetFirst("nam")                                 // want `prompter ID "nam" is referenced but never registered, did you mean "name" \?`
+
+	return p
+}
@@ -0,0 +1,15 @@
+package b
+
+import (
+	"prompters"
+
+	"github.com/antham/strumt/v2"
+)
+
+func newPrompts() strumt.Prompts {
+	p := strumt.NewPromptsFromReaderAndWriter(nil, nil)
+	p.AddLinePrompter(&prompters.ShellPrompt{}) // want `prompter ID "editor" is referenced but never registered`
+	p.SetFirst("shell")
+
+	return p
+}
@@ -0,0 +1,33 @@
+package dynamic
+
+import (
+	"fmt"
+
+	"github.com/antham/strumt/v2"
+)
+
+type IndexPrompt struct {
+	index int
+}
+
+func (i *IndexPrompt) ID() string { return fmt.Sprintf("prompt%d", i.index) } // want ID:`returns\(\?\)`
+
+func (i *IndexPrompt) PromptString() string { return "Give a value" }
+
+func (i *IndexPrompt) Parse(value string) error { return nil }
+
+func (i *IndexPrompt) NextOnSuccess(value string) string { return fmt.Sprintf("prompt%d", i.index+1) } // want NextOnSuccess:`returns\(\?\)`
+
+func (i *IndexPrompt) NextOnError(err error) string { return "unknown" } // want NextOnError:`returns\("unknown"\)`
+
+func newPrompts() strumt.Prompts {
+	p := strumt.NewPromptsFromReaderAndWriter(nil, nil)
+
+	for i := 0; i < 3; i++ {
+		p.AddLinePrompter(&IndexPrompt{i})
+	}
+
+	p.SetFirst("prompt0")
+
+	return p
+}
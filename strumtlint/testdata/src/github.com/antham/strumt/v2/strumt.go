@@ -0,0 +1,32 @@
+// Package strumt is a stub of the strumt API used by the analyzer
+package strumt
+
+type Prompter interface {
+	ID() string
+	PromptString() string
+	NextOnError(error) string
+}
+
+type LinePrompter interface {
+	Prompter
+	NextOnSuccess(value string) string
+	Parse(value string) error
+}
+
+type MultilinePrompter interface {
+	Prompter
+	NextOnSuccess(value []string) string
+	Parse(value []string) error
+}
+
+type Group struct{}
+
+type Prompts struct{}
+
+func NewPromptsFromReaderAndWriter(interface{}, interface{}) Prompts { return Prompts{} }
+
+func (p *Prompts) AddLinePrompter(prompt LinePrompter)              {}
+func (p *Prompts) AddMultilinePrompter(prompt MultilinePrompter)    {}
+func (p *Prompts) SetFirst(id string)                               {}
+func (p *Prompts) AddSubflow(id string, flow *Prompts, next string) {}
+func (p *Prompts) AddGroup(id string, group *Group, next string)    {}
@@ -0,0 +1,13 @@
+package prompters
+
+type ShellPrompt struct{}
+
+func (s *ShellPrompt) ID() string { return "shell" } // want ID:`returns\("shell"\)`
+
+func (s *ShellPrompt) PromptString() string { return "Choose a shell" }
+
+func (s *ShellPrompt) Parse(value string) error { return nil }
+
+func (s *ShellPrompt) NextOnSuccess(value string) string { return "editor" } // want NextOnSuccess:`returns\("editor"\)`
+
+func (s *ShellPrompt) NextOnError(err error) string { return "shell" } // want NextOnError:`returns\("shell"\)`
@@ -0,0 +1,251 @@
+// Package strumttest provides utilities to test prompt sequences
+// built with strumt
+package strumttest
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/antham/strumt/v2"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DefaultTimeout is the time a Console waits for an expected output
+// or for the prompt sequence to end before failing the test
+const DefaultTimeout = 5 * time.Second
+
+// Factory builds the prompts to test from the given reader and writer,
+// most of the time using strumt.NewPromptsFromReaderAndWriter
+type Factory func(io.Reader, io.Writer) strumt.Prompts
+
+// Console runs a prompt sequence in background and lets
+// a test interact with it like a user would do, expecting
+// outputs and sending inputs
+type Console struct {
+	t       testing.TB
+	timeout time.Duration
+	input   *io.PipeWriter
+	output  *output
+	offset  int
+	prompts strumt.Prompts
+	done    chan struct{}
+}
+
+// New starts the prompts built by factory and returns a Console
+// to interact with them, the prompt sequence is stopped
+// when the test ends
+func New(t testing.TB, factory Factory) *Console {
+	reader, writer := io.Pipe()
+	c := &Console{
+		t:       t,
+		timeout: DefaultTimeout,
+		input:   writer,
+		output:  newOutput(),
+		done:    make(chan struct{}),
+	}
+
+	c.prompts = factory(reader, c.output)
+
+	go func() {
+		defer close(c.done)
+		defer reader.Close()
+
+		c.prompts.Run()
+	}()
+
+	t.Cleanup(c.stop)
+
+	return c
+}
+
+// SetTimeout changes the time the console waits for an expectation
+func (c *Console) SetTimeout(timeout time.Duration) *Console {
+	c.timeout = timeout
+
+	return c
+}
+
+// Expect waits until the output contains the given string,
+// only output displayed after the previous expectation is looked up
+func (c *Console) Expect(expected string) *Console {
+	c.t.Helper()
+
+	deadline := time.After(c.timeout)
+	ended := false
+
+	for {
+		content, changed := c.output.content()
+		unread := content[c.offset:]
+
+		if i := strings.Index(unread, expected); i != -1 {
+			c.offset += i + len(expected)
+
+			return c
+		}
+
+		if ended {
+			c.t.Fatalf("prompt sequence ended before output was displayed\n%s", diff(expected, unread))
+			return c
+		}
+
+		select {
+		case <-changed:
+		case <-c.done:
+			ended = true
+		case <-deadline:
+			c.t.Fatalf("timeout after %s waiting for output\n%s", c.timeout, diff(expected, unread))
+			return c
+		}
+	}
+}
+
+// ExpectError waits until the given error is displayed, it works like Expect
+// but makes tests more readable
+func (c *Console) ExpectError(expected string) *Console {
+	c.t.Helper()
+
+	return c.Expect(expected)
+}
+
+// Send submits a line to the running prompt
+func (c *Console) Send(input string) *Console {
+	c.t.Helper()
+
+	return c.write(input + "\n")
+}
+
+// SendLines submits several lines to a running multiline prompt
+// and ends the input with an empty line
+func (c *Console) SendLines(inputs ...string) *Console {
+	c.t.Helper()
+
+	return c.write(strings.Join(inputs, "\n") + "\n\n")
+}
+
+// ExpectEnd waits until the prompt sequence ends
+func (c *Console) ExpectEnd() *Console {
+	c.t.Helper()
+
+	select {
+	case <-c.done:
+	case <-time.After(c.timeout):
+		content, _ := c.output.content()
+		c.t.Fatalf("timeout after %s waiting for prompt sequence to end, last output :\n%s", c.timeout, content[c.offset:])
+	}
+
+	return c
+}
+
+// Output returns everything displayed so far
+func (c *Console) Output() string {
+	content, _ := c.output.content()
+
+	return content
+}
+
+// Scenario returns the scenario of the prompt sequence,
+// it waits for the sequence to end
+func (c *Console) Scenario() []strumt.Step {
+	c.t.Helper()
+
+	c.ExpectEnd()
+
+	return c.prompts.Scenario()
+}
+
+func (c *Console) write(input string) *Console {
+	c.t.Helper()
+
+	select {
+	case <-c.done:
+		c.t.Fatalf("can't send %q : prompt sequence has ended", strings.TrimSuffix(input, "\n"))
+		return c
+	default:
+	}
+
+	written := make(chan error, 1)
+
+	go func() {
+		_, err := io.WriteString(c.input, input)
+		written <- err
+	}()
+
+	select {
+	case err := <-written:
+		c.checkWrite(input, err)
+	case <-c.done:
+		// input reader is closed when the sequence ends, so the write
+		// returns right away, successfully if the input has been consumed
+		if err := <-written; err == io.ErrClosedPipe {
+			c.t.Fatalf("can't send %q : prompt sequence has ended", strings.TrimSuffix(input, "\n"))
+		} else {
+			c.checkWrite(input, err)
+		}
+	case <-time.After(c.timeout):
+		c.t.Fatalf("timeout after %s sending %q", c.timeout, strings.TrimSuffix(input, "\n"))
+	}
+
+	return c
+}
+
+func (c *Console) checkWrite(input string, err error) {
+	c.t.Helper()
+
+	if err != nil {
+		c.t.Fatalf("can't send %q : %s", strings.TrimSuffix(input, "\n"), err)
+	}
+}
+
+func (c *Console) stop() {
+	c.input.Close()
+
+	select {
+	case <-c.done:
+	case <-time.After(c.timeout):
+	}
+}
+
+func diff(expected string, actual string) string {
+	d, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expected),
+		B:        difflib.SplitLines(actual),
+		FromFile: "Expected",
+		ToFile:   "Actual",
+		Context:  3,
+	})
+
+	return d
+}
+
+// output is a writer notifying readers each time something is written
+type output struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	changed chan struct{}
+}
+
+func newOutput() *output {
+	return &output{changed: make(chan struct{})}
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	n, err := o.buf.Write(p)
+	close(o.changed)
+	o.changed = make(chan struct{})
+
+	return n, err
+}
+
+func (o *output) content() (string, <-chan struct{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.buf.String(), o.changed
+}
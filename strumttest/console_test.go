@@ -0,0 +1,243 @@
+package strumttest
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antham/strumt/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type StringPrompt struct {
+	store             *string
+	prompt            string
+	currentID         string
+	nextPrompt        string
+	nextPromptOnError string
+}
+
+func (s *StringPrompt) ID() string {
+	return s.currentID
+}
+
+func (s *StringPrompt) PromptString() string {
+	return s.prompt
+}
+
+func (s *StringPrompt) Parse(value string) error {
+	if value == "" {
+		return fmt.Errorf("Empty value given")
+	}
+
+	*(s.store) = value
+
+	return nil
+}
+
+func (s *StringPrompt) NextOnSuccess(value string) string {
+	return s.nextPrompt
+}
+
+func (s *StringPrompt) NextOnError(err error) string {
+	return s.nextPromptOnError
+}
+
+type IntPrompt struct {
+	store             *int
+	prompt            string
+	currentID         string
+	nextPrompt        string
+	nextPromptOnError string
+}
+
+func (i *IntPrompt) ID() string {
+	return i.currentID
+}
+
+func (i *IntPrompt) PromptString() string {
+	return i.prompt
+}
+
+func (i *IntPrompt) Parse(value string) error {
+	age, err := strconv.Atoi(value)
+
+	if err != nil {
+		return fmt.Errorf("%s is not a valid number", value)
+	}
+
+	*(i.store) = age
+
+	return nil
+}
+
+func (i *IntPrompt) NextOnSuccess(value string) string {
+	return i.nextPrompt
+}
+
+func (i *IntPrompt) NextOnError(err error) string {
+	return i.nextPromptOnError
+}
+
+type SlicePrompt struct {
+	store *[]string
+}
+
+func (s *SlicePrompt) ID() string {
+	return "slice"
+}
+
+func (s *SlicePrompt) PromptString() string {
+	return "Give several inputs"
+}
+
+func (s *SlicePrompt) Parse(values []string) error {
+	*(s.store) = values
+
+	return nil
+}
+
+func (s *SlicePrompt) NextOnSuccess(values []string) string {
+	return ""
+}
+
+func (s *SlicePrompt) NextOnError(err error) string {
+	return "slice"
+}
+
+type fakeT struct {
+	testing.TB
+	failures []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Cleanup(func()) {}
+
+func (f *fakeT) Fatalf(format string, args ...interface{}) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+type fakeErrorT struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeErrorT) Helper() {}
+
+func (f *fakeErrorT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func newUserFactory(name *string, age *int) Factory {
+	return func(reader io.Reader, writer io.Writer) strumt.Prompts {
+		p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+		p.AddLinePrompter(&StringPrompt{name, "Enter your name", "name", "age", "name"})
+		p.AddLinePrompter(&IntPrompt{age, "Enter your age", "age", "", "age"})
+		p.SetFirst("name")
+
+		return p
+	}
+}
+
+func TestConsole(t *testing.T) {
+	var name string
+	var age int
+
+	c := New(t, newUserFactory(&name, &age))
+	c.Expect("Enter your name").
+		Send("").
+		ExpectError("Empty value given").
+		Expect("Enter your name").
+		Send("John").
+		Expect("Enter your age").
+		Send("whatever").
+		ExpectError("whatever is not a valid number").
+		Send("31").
+		ExpectEnd()
+
+	assert.Equal(t, "John", name)
+	assert.Equal(t, 31, age)
+	assert.Len(t, c.Scenario(), 4)
+	assert.Equal(t, "Enter your name\nEmpty value given\n\nEnter your name\n\nEnter your age\nwhatever is not a valid number\n\nEnter your age\n", c.Output())
+}
+
+func TestConsoleSendLines(t *testing.T) {
+	var values []string
+
+	c := New(t, func(reader io.Reader, writer io.Writer) strumt.Prompts {
+		p := strumt.NewPromptsFromReaderAndWriter(reader, writer)
+		p.AddMultilinePrompter(&SlicePrompt{&values})
+		p.SetFirst("slice")
+
+		return p
+	})
+	c.Expect("Give several inputs").SendLines("a", "b", "c").ExpectEnd()
+
+	assert.Equal(t, []string{"a", "b", "c"}, values)
+}
+
+func TestConsoleFailures(t *testing.T) {
+	type scenario struct {
+		name     string
+		test     func(c *Console)
+		failures []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Expect output never displayed",
+			func(c *Console) {
+				c.Expect("Enter your firstname")
+			},
+			[]string{"timeout after 50ms waiting for output\n--- Expected\n+++ Actual\n@@ -1 +1,2 @@\n-Enter your firstname\n+Enter your name\n+\n"},
+		},
+		{
+			"Expect output after the end of the sequence",
+			func(c *Console) {
+				c.Send("John").Send("31").Expect("Enter your email")
+			},
+			[]string{"prompt sequence ended before output was displayed\n--- Expected\n+++ Actual\n@@ -1 +1,4 @@\n-Enter your email\n+Enter your name\n+\n+Enter your age\n+\n"},
+		},
+		{
+			"Expect end of a running sequence",
+			func(c *Console) {
+				c.Send("John").ExpectEnd()
+			},
+			[]string{"timeout after 50ms waiting for prompt sequence to end, last output :\nEnter your name\n\nEnter your age\n"},
+		},
+		{
+			"Send input after the end of the sequence",
+			func(c *Console) {
+				c.Send("John").Send("31").ExpectEnd().Send("test")
+			},
+			[]string{"can't send \"test\" : prompt sequence has ended"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			f := &fakeT{}
+			c := New(f, newUserFactory(new(string), new(int))).SetTimeout(50 * time.Millisecond)
+			defer c.stop()
+
+			s.test(c)
+
+			assert.Equal(t, s.failures, f.failures)
+		})
+	}
+}
+
+func TestConsoleStopsRunningSequence(t *testing.T) {
+	f := &fakeT{}
+	c := New(f, newUserFactory(new(string), new(int)))
+	c.Expect("Enter your name")
+	c.stop()
+
+	assert.True(t, strings.HasSuffix(c.Output(), "Enter your name\n"))
+	assert.Len(t, c.Scenario(), 1)
+	assert.Empty(t, f.failures)
+}
@@ -0,0 +1,124 @@
+package strumttest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/antham/strumt/v2"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// UpdateEnv is the environment variable which, when set to true,
+// makes Golden write golden files instead of comparing them
+const UpdateEnv = "STRUMTTEST_UPDATE"
+
+// Golden runs the prompts built by factory with the given inputs,
+// one input per line, and compares the transcript of the session
+// (everything displayed and the scenario) against the golden file
+// testdata/<name>.golden. When UpdateEnv is true or when the test
+// binary defines an -update flag which is set, the golden file
+// is written instead
+func Golden(t testing.TB, name string, factory Factory, inputs ...string) {
+	t.Helper()
+
+	actual := Transcript(factory, inputs...)
+	file := filepath.Join("testdata", name+".golden")
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("can't create golden file directory : %s", err)
+		}
+
+		if err := os.WriteFile(file, []byte(actual), 0o644); err != nil {
+			t.Fatalf("can't write golden file : %s", err)
+		}
+
+		return
+	}
+
+	expected, err := os.ReadFile(file)
+
+	if err != nil {
+		t.Fatalf("can't read golden file, run tests with "+UpdateEnv+"=true to create it : %s", err)
+		return
+	}
+
+	if string(expected) != actual {
+		d, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(expected)),
+			B:        difflib.SplitLines(actual),
+			FromFile: file,
+			ToFile:   "Actual",
+			Context:  3,
+		})
+
+		t.Errorf("transcript doesn't match golden file, run tests with "+UpdateEnv+"=true to update it\n%s", d)
+	}
+}
+
+// updating reports whether golden files must be written, the
+// -update flag isn't defined here since it would conflict with
+// test binaries defining it themselves
+func updating() bool {
+	if f := flag.Lookup("update"); f != nil {
+		if g, ok := f.Value.(flag.Getter); ok {
+			if update, ok := g.Get().(bool); ok && update {
+				return true
+			}
+		}
+	}
+
+	update, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+
+	return update
+}
+
+// Transcript runs the prompts built by factory with the given inputs,
+// one input per line, and returns everything displayed followed
+// by the scenario of the session
+func Transcript(factory Factory, inputs ...string) string {
+	var input bytes.Buffer
+	var output bytes.Buffer
+
+	for _, i := range inputs {
+		input.WriteString(i + "\n")
+	}
+
+	p := factory(&input, &output)
+	p.Run()
+
+	var transcript strings.Builder
+
+	transcript.WriteString("--- output ---\n")
+	transcript.WriteString(output.String())
+
+	if output.Len() > 0 && !strings.HasSuffix(output.String(), "\n") {
+		transcript.WriteString("\n")
+	}
+
+	transcript.WriteString("--- scenario ---\n")
+
+	for _, step := range p.Scenario() {
+		writeStep(&transcript, step)
+	}
+
+	return transcript.String()
+}
+
+func writeStep(w *strings.Builder, step strumt.Step) {
+	fmt.Fprintf(w, "prompt: %s\n", step.PromptString())
+
+	for _, input := range step.Inputs() {
+		fmt.Fprintf(w, "input: %q\n", input)
+	}
+
+	if step.Error() != nil {
+		fmt.Fprintf(w, "error: %s\n", step.Error())
+	}
+}
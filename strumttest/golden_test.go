@@ -0,0 +1,75 @@
+package strumttest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// update is defined like a test binary would do
+// to check it doesn't conflict with Golden
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	Golden(t, "user", newUserFactory(new(string), new(int)), "", "John", "whatever", "31")
+}
+
+func TestGoldenMismatch(t *testing.T) {
+	f := &fakeErrorT{}
+
+	Golden(f, "user", newUserFactory(new(string), new(int)), "John", "31")
+
+	assert.Len(t, f.errors, 1)
+	assert.True(t, strings.HasPrefix(f.errors[0], "transcript doesn't match golden file, run tests with STRUMTTEST_UPDATE=true to update it\n--- testdata/user.golden\n+++ Actual\n"))
+	assert.Contains(t, f.errors[0], "-error: Empty value given\n")
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	type scenario struct {
+		name  string
+		setup func(t *testing.T)
+	}
+
+	scenarios := []scenario{
+		{
+			"Environment variable",
+			func(t *testing.T) {
+				t.Setenv(UpdateEnv, "true")
+			},
+		},
+		{
+			"Flag defined by the test binary",
+			func(t *testing.T) {
+				*update = true
+				t.Cleanup(func() { *update = false })
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			dir := t.TempDir()
+			wd, err := os.Getwd()
+			assert.NoError(t, err)
+			assert.NoError(t, os.Chdir(dir))
+			defer func() { assert.NoError(t, os.Chdir(wd)) }()
+
+			s.setup(t)
+
+			Golden(t, "user", newUserFactory(new(string), new(int)), "John", "31")
+
+			content, err := os.ReadFile(filepath.Join(dir, "testdata", "user.golden"))
+
+			assert.NoError(t, err)
+			assert.Equal(t, Transcript(newUserFactory(new(string), new(int)), "John", "31"), string(content))
+		})
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	assert.Equal(t, "--- output ---\nEnter your name\n\nEnter your age\n--- scenario ---\nprompt: Enter your name\ninput: \"John\"\nprompt: Enter your age\nerror: EOF\n", Transcript(newUserFactory(new(string), new(int)), "John"))
+}
@@ -0,0 +1,94 @@
+package strumt
+
+// scope defines how IDs returned by a prompter are resolved,
+// prompters of a subflow see IDs of their own flow which are
+// prefixed by the subflow ID, and the end of the subflow
+// leads to its exit
+type scope struct {
+	prefix string
+	exit   string
+}
+
+// AddSubflow adds all prompters of flow as a single prompter
+// referenced by id. Prompters of flow are registered under
+// the "id.prompterID" reference so they don't collide with
+// other prompters, they keep on using their own IDs to reference
+// each other. When flow ends, the prompt sequence goes on with
+// the prompter referenced by next, an empty next ends the flow
+// flow is added to. A prompter of flow returning an empty ID from
+// NextOnError ends the whole prompt sequence as it would at top level.
+// Reader and writer of flow are not used
+func (p *Prompts) AddSubflow(id string, flow *Prompts, next string) {
+	prefix := id + "."
+
+	for key, prompt := range flow.prompts {
+		s := flow.scopes[key]
+		exit := next
+
+		if s.exit != "" {
+			exit = prefix + s.exit
+		}
+
+		p.prompts[prefix+key] = prompt
+		p.scopes[prefix+key] = scope{prefix + s.prefix, exit}
+	}
+
+	for key, more := range flow.groups {
+		p.groups[prefix+key] = more
+	}
+
+	for alias, key := range flow.entries {
+		p.entries[prefix+alias] = prefix + key
+	}
+
+	p.entries[id] = prefix + flow.first
+}
+
+// entry follows subflow references to find
+// the prompter a key refers to
+func (p *Prompts) entry(key string) string {
+	for {
+		target, ok := p.entries[key]
+
+		if !ok {
+			return key
+		}
+
+		key = target
+	}
+}
+
+// resolve returns the key of the prompter referenced
+// by id from the prompter registered under key, failed tells
+// whether id comes from NextOnError. When the prompter is the
+// end of a group iteration, the group decides where to go, an
+// iteration is never ended by a failing prompter
+func (p *Prompts) resolve(key string, id string, failed bool) string {
+	target := p.target(key, id, failed)
+
+	if more, ok := p.groups[target]; ok && key != target && !failed {
+		if id, ask := more.end(); !ask {
+			return p.resolve(target, id, false)
+		}
+	}
+
+	return target
+}
+
+// target returns the key of the prompter referenced by id from
+// the prompter registered under key, an empty id leads to the exit
+// of the subflow unless it comes from NextOnError
+func (p *Prompts) target(key string, id string, failed bool) string {
+	if id == "" && failed {
+		return ""
+	}
+
+	s := p.scopes[key]
+	target := s.exit
+
+	if id != "" {
+		target = s.prefix + id
+	}
+
+	return p.entry(target)
+}
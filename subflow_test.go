@@ -0,0 +1,104 @@
+package strumt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Connection struct {
+	Host string
+	Port int
+}
+
+func newConnectionFlow(c *Connection) *Prompts {
+	p := NewPromptsFromReaderAndWriter(nil, nil)
+	p.AddLinePrompter(&StringPrompt{&c.Host, "Give a host", "host", "port", "host"})
+	p.AddLinePrompter(&IntPrompt{&c.Port, "Give a port", "port", "", "port"})
+	p.SetFirst("host")
+
+	return &p
+}
+
+func TestPromptsRunWithSubflows(t *testing.T) {
+	var username string
+	var password string
+	database := Connection{}
+	cache := Connection{}
+
+	buf := "user\ndb.local\ntest\n5432\ncache.local\n6379\npassword\n"
+
+	var actualStdout bytes.Buffer
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString(buf), &actualStdout)
+	p.AddLinePrompter(&StringPrompt{&username, "Give a username", "username", "database", "username"})
+	p.AddSubflow("database", newConnectionFlow(&database), "cache")
+	p.AddSubflow("cache", newConnectionFlow(&cache), "password")
+	p.AddLinePrompter(&StringPrompt{&password, "Give a password", "password", "", "password"})
+	p.SetFirst("username")
+	p.Run()
+
+	assert.Equal(t, "user", username)
+	assert.Equal(t, Connection{"db.local", 5432}, database)
+	assert.Equal(t, Connection{"cache.local", 6379}, cache)
+	assert.Equal(t, "password", password)
+	assert.Len(t, p.Scenario(), 7)
+	assert.Equal(t, "Give a username\n\nGive a host\n\nGive a port\nProvide a numerical value\n\nGive a port\n\nGive a host\n\nGive a port\n\nGive a password\n", actualStdout.String())
+}
+
+func TestPromptsRunWithNestedSubflows(t *testing.T) {
+	var name string
+	primary := Connection{}
+	replica := Connection{}
+
+	cluster := NewPromptsFromReaderAndWriter(nil, nil)
+	cluster.AddSubflow("primary", newConnectionFlow(&primary), "replica")
+	cluster.AddSubflow("replica", newConnectionFlow(&replica), "")
+	cluster.SetFirst("primary")
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("primary.local\n5432\nreplica.local\n5433\nmain\n"), ioutil.Discard)
+	p.AddSubflow("cluster", &cluster, "name")
+	p.AddLinePrompter(&StringPrompt{&name, "Give a name", "name", "", "name"})
+	p.SetFirst("cluster")
+	p.Run()
+
+	assert.Equal(t, Connection{"primary.local", 5432}, primary)
+	assert.Equal(t, Connection{"replica.local", 5433}, replica)
+	assert.Equal(t, "main", name)
+}
+
+func TestPromptsRunEndingWithSubflow(t *testing.T) {
+	database := Connection{}
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("db.local\n5432\n"), ioutil.Discard)
+	p.AddSubflow("database", newConnectionFlow(&database), "")
+	p.SetFirst("database")
+	p.Run()
+
+	assert.Equal(t, Connection{"db.local", 5432}, database)
+	assert.Len(t, p.Scenario(), 2)
+	assert.NoError(t, p.Scenario()[1].Error())
+}
+
+func TestPromptsRunEndingOnErrorInSubflow(t *testing.T) {
+	var name string
+	database := Connection{}
+
+	flow := NewPromptsFromReaderAndWriter(nil, nil)
+	flow.AddLinePrompter(&StringPrompt{&database.Host, "Give a host", "host", "port", ""})
+	flow.AddLinePrompter(&IntPrompt{&database.Port, "Give a port", "port", "", "port"})
+	flow.SetFirst("host")
+
+	p := NewPromptsFromReaderAndWriter(bytes.NewBufferString("\nmain\n"), ioutil.Discard)
+	p.AddSubflow("database", &flow, "name")
+	p.AddLinePrompter(&StringPrompt{&name, "Give a name", "name", "", "name"})
+	p.SetFirst("database")
+	p.Run()
+
+	assert.Empty(t, name)
+	assert.Len(t, p.Scenario(), 1)
+	assert.EqualError(t, p.Scenario()[0].Error(), "Empty value given")
+	assert.Empty(t, p.Scenario()[0].Next())
+}
@@ -0,0 +1,259 @@
+package strumt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+const snapshotVersion = 1
+
+// ErrFingerprintMismatch is returned when resuming a prompt
+// sequence saved from a different flow definition
+var ErrFingerprintMismatch = errors.New("prompt sequence definition has changed since it was saved")
+
+type savedStep struct {
+	ID       string    `json:"id"`
+	Prompt   string    `json:"prompt"`
+	Inputs   []string  `json:"inputs"`
+	Error    string    `json:"error,omitempty"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Next     string    `json:"next,omitempty"`
+	Source   Source    `json:"source"`
+	Attempt  int       `json:"attempt"`
+	Redacted bool      `json:"redacted,omitempty"`
+	Help     bool      `json:"help,omitempty"`
+}
+
+type snapshot struct {
+	Version     int                 `json:"version"`
+	Fingerprint string              `json:"fingerprint"`
+	Current     string              `json:"current"`
+	Steps       []savedStep         `json:"steps"`
+	Answers     map[string][]string `json:"answers"`
+}
+
+// Fingerprint returns a hash identifying the flow definition,
+// it changes when prompters are added, removed, replaced by prompters
+// of another type or when their prompt string, declared transitions
+// or choices change
+func (p *Prompts) Fingerprint() string {
+	keys := []string{}
+
+	for key := range p.prompts {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "first:%s\n", p.entry(p.first))
+
+	for _, key := range keys {
+		prompt := p.prompts[key]
+		fmt.Fprintf(h, "%s:%T:%s:%q\n", key, prompt, p.scopes[key], prompt.PromptString())
+
+		if t, ok := prompt.(TransitionPrompter); ok {
+			transitions := append([]string{}, t.Transitions()...)
+			sort.Strings(transitions)
+			fmt.Fprintf(h, "transitions:%q\n", transitions)
+		}
+
+		if c, ok := prompt.(ChoicePrompter); ok {
+			fmt.Fprintf(h, "choices:%q\n", c.Choices())
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Save writes the history of the running prompt sequence, the prompter
+// waiting for inputs and answers collected so far. It can be called
+// at any time between two steps, for instance when the user asks to stop
+// or once the sequence has been aborted because the reader failed.
+// Inputs of sensitive prompters are redacted. Steps recorded during
+// the review are left out, the saved sequence ends before it
+func (p *Prompts) Save(writer io.Writer) error {
+	s := snapshot{
+		Version:     snapshotVersion,
+		Fingerprint: p.Fingerprint(),
+		Current:     p.current,
+		Steps:       []savedStep{},
+		Answers:     map[string][]string{},
+	}
+
+	steps := p.scenario
+
+	if p.reviewFrom > 0 {
+		steps = steps[:p.reviewFrom]
+		s.Current = ""
+	}
+
+	for _, step := range steps {
+		step = p.redact(step)
+
+		if step.aborted {
+			s.Current = step.id
+			continue
+		}
+
+		saved := savedStep{
+			ID:       step.id,
+			Prompt:   step.prompt,
+			Inputs:   step.inputs,
+			Start:    step.start,
+			End:      step.end,
+			Next:     step.next,
+			Source:   step.source,
+			Attempt:  step.attempt,
+			Redacted: isSensitive(p.prompts[step.id]),
+			Help:     step.help,
+		}
+
+		if step.err != nil {
+			saved.Error = step.err.Error()
+		}
+
+		// answers are taken from saved steps rather than from
+		// the session so they don't include changes made during
+		// the review
+		if step.err == nil && !step.help && !saved.Redacted {
+			s.Answers[step.id] = step.inputs
+		}
+
+		s.Steps = append(s.Steps, saved)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(s)
+}
+
+// Resume restores a prompt sequence written by Save, saved inputs
+// are submitted again to prompters so they can rebuild their state.
+// The next call to Run goes on from where the sequence stopped, or from
+// the first sensitive prompter as its inputs were not saved.
+// It fails if the flow definition changed since the sequence was saved,
+// if a prompter doesn't accept or reject saved inputs as it did or if
+// the replayed sequence doesn't end on the saved prompter and answers
+func (p *Prompts) Resume(reader io.Reader) error {
+	s := snapshot{}
+
+	if err := json.NewDecoder(reader).Decode(&s); err != nil {
+		return fmt.Errorf("can't decode saved prompt sequence : %s", err)
+	}
+
+	if s.Version != snapshotVersion {
+		return fmt.Errorf("unsupported saved prompt sequence version %d", s.Version)
+	}
+
+	if s.Fingerprint != p.Fingerprint() {
+		return ErrFingerprintMismatch
+	}
+
+	// replayed steps have already been logged
+	logger := p.logger
+	p.logger = nil
+	defer func() {
+		p.logger = logger
+	}()
+
+	p.Start()
+
+	redacted := false
+
+	for i, saved := range s.Steps {
+		// inputs taken from the environment are submitted again by
+		// the prompt sequence itself, replaying stops at the first
+		// sensitive prompter which is asked again
+		if saved.Source != SourceEnv {
+			if p.current != saved.ID {
+				return fmt.Errorf("can't replay step %d : expected prompter %s got %s", i+1, saved.ID, p.current)
+			}
+
+			if saved.Redacted {
+				redacted = true
+				break
+			}
+
+			if saved.Help {
+				p.help(saved.Inputs, saved.Source)
+			} else {
+				p.submit(saved.Inputs, saved.Source)
+			}
+		}
+
+		if i >= len(p.scenario) || p.scenario[i].id != saved.ID {
+			return fmt.Errorf("can't replay step %d of prompter %s", i+1, saved.ID)
+		}
+
+		if err := p.scenario[i].err; (err == nil) != (saved.Error == "") {
+			return fmt.Errorf("can't replay step %d of prompter %s : inputs are no longer handled the same way", i+1, saved.ID)
+		}
+
+		p.scenario[i].start = saved.Start
+		p.scenario[i].end = saved.End
+	}
+
+	// a replay stopped at a sensitive prompter is expected
+	// to end before the saved prompter
+	if !redacted {
+		if err := p.checkReplay(s); err != nil {
+			return err
+		}
+	}
+
+	p.since = p.now()
+	p.resumed = true
+
+	return nil
+}
+
+// checkReplay returns an error when the replayed sequence
+// doesn't end on the saved prompter and answers
+func (p *Prompts) checkReplay(s snapshot) error {
+	if p.current != s.Current {
+		return fmt.Errorf("can't replay saved prompt sequence : expected to stop at prompter %s got %s", s.Current, p.current)
+	}
+
+	count := 0
+
+	for id, answer := range p.session.answers {
+		if isSensitive(p.prompts[id]) {
+			continue
+		}
+
+		count++
+
+		if saved, ok := s.Answers[id]; !ok || !equalInputs(answer.inputs, saved) {
+			return fmt.Errorf("can't replay saved prompt sequence : answer of prompter %s doesn't match the saved one", id)
+		}
+	}
+
+	if count != len(s.Answers) {
+		return fmt.Errorf("can't replay saved prompt sequence : answers don't match saved answers")
+	}
+
+	return nil
+}
+
+func equalInputs(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,228 @@
+package strumt
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSuspendFlow(reader io.Reader, writer io.Writer, username *string, port *int, ips *[]string) Prompts {
+	p := NewPromptsFromReaderAndWriter(reader, writer)
+	p.AddLinePrompter(&StringPrompt{username, "Give a username", "username", "port", "username"})
+	p.AddLinePrompter(&IntPrompt{port, "Give a port", "port", "ips", "port"})
+	p.AddMultilinePrompter(&IpsPrompt{ips, "Give some ips", "ips", "", "ips"})
+	p.SetFirst("username")
+
+	return p
+}
+
+func TestPromptsSaveAndResume(t *testing.T) {
+	var username string
+	var port int
+	var ips []string
+
+	p := newSuspendFlow(bytes.NewBufferString("\nuser\ntest\n10000\n"), &bytes.Buffer{}, &username, &port, &ips)
+	p.Run()
+
+	state := bytes.Buffer{}
+	assert.NoError(t, p.Save(&state))
+
+	s := snapshot{}
+	assert.NoError(t, json.Unmarshal(state.Bytes(), &s))
+	assert.Equal(t, "ips", s.Current)
+	assert.Len(t, s.Steps, 4)
+	assert.Equal(t, map[string][]string{"username": {"user"}, "port": {"10000"}}, s.Answers)
+
+	username, port = "", 0
+	output := bytes.Buffer{}
+
+	r := newSuspendFlow(bytes.NewBufferString("127.0.0.1\n\n"), &output, &username, &port, &ips)
+	assert.NoError(t, r.Resume(bytes.NewReader(state.Bytes())))
+	assert.Equal(t, "ips", r.Current())
+	assert.Equal(t, "user", username)
+	assert.Equal(t, 10000, port)
+
+	r.Run()
+
+	assert.Equal(t, "Give some ips\n", output.String())
+	assert.Equal(t, []string{"127.0.0.1"}, ips)
+	assert.Len(t, r.Scenario(), 5)
+
+	for i, step := range p.Scenario()[:4] {
+		assert.Equal(t, step.ID(), r.Scenario()[i].ID())
+		assert.Equal(t, step.Inputs(), r.Scenario()[i].Inputs())
+		assert.Equal(t, step.Error(), r.Scenario()[i].Error())
+		assert.Equal(t, step.Attempt(), r.Scenario()[i].Attempt())
+		assert.True(t, step.Start().Equal(r.Scenario()[i].Start()))
+		assert.True(t, step.End().Equal(r.Scenario()[i].End()))
+	}
+}
+
+func TestPromptsResumeFailures(t *testing.T) {
+	type scenario struct {
+		name  string
+		setup func(p *Prompts)
+		state func(p *Prompts) string
+		err   string
+	}
+
+	save := func(p *Prompts) string {
+		p.Run()
+		state := bytes.Buffer{}
+		assert.NoError(t, p.Save(&state))
+
+		return state.String()
+	}
+
+	scenarios := []scenario{
+		{
+			"Invalid content",
+			func(p *Prompts) {},
+			func(p *Prompts) string { return "whatever" },
+			"can't decode saved prompt sequence : invalid character 'w' looking for beginning of value",
+		},
+		{
+			"Unsupported version",
+			func(p *Prompts) {},
+			func(p *Prompts) string { return `{"version":2}` },
+			"unsupported saved prompt sequence version 2",
+		},
+		{
+			"Prompter added",
+			func(p *Prompts) {
+				p.AddLinePrompter(&StringPrompt{new(string), "Give a name", "name", "", ""})
+			},
+			save,
+			ErrFingerprintMismatch.Error(),
+		},
+		{
+			"First prompter changed",
+			func(p *Prompts) {
+				p.SetFirst("port")
+			},
+			save,
+			ErrFingerprintMismatch.Error(),
+		},
+		{
+			"Prompt string changed",
+			func(p *Prompts) {
+				p.AddLinePrompter(&StringPrompt{new(string), "Give a login", "username", "port", "username"})
+			},
+			save,
+			ErrFingerprintMismatch.Error(),
+		},
+		{
+			"Inputs no longer accepted",
+			func(p *Prompts) {},
+			func(p *Prompts) string {
+				state := save(p)
+
+				return strings.Replace(state, `"10000"`, `"whatever"`, 1)
+			},
+			"can't replay step 2 of prompter port : inputs are no longer handled the same way",
+		},
+		{
+			"History doesn't match the flow",
+			func(p *Prompts) {},
+			func(p *Prompts) string {
+				state := save(p)
+
+				return strings.Replace(state, `"id": "port"`, `"id": "ips"`, 1)
+			},
+			"can't replay step 2 : expected prompter ips got port",
+		},
+		{
+			"Current prompter doesn't match",
+			func(p *Prompts) {},
+			func(p *Prompts) string {
+				state := save(p)
+
+				return strings.Replace(state, `"current": "ips"`, `"current": "port"`, 1)
+			},
+			"can't replay saved prompt sequence : expected to stop at prompter port got ips",
+		},
+		{
+			"Answers don't match",
+			func(p *Prompts) {},
+			func(p *Prompts) string {
+				state := save(p)
+				i := strings.LastIndex(state, `"user"`)
+
+				return state[:i] + `"other"` + state[i+len(`"user"`):]
+			},
+			"can't replay saved prompt sequence : answer of prompter username doesn't match the saved one",
+		},
+		{
+			"Answer added",
+			func(p *Prompts) {},
+			func(p *Prompts) string {
+				state := save(p)
+
+				return strings.Replace(state, `"answers": {`, `"answers": {"ips": ["127.0.0.1"],`, 1)
+			},
+			"can't replay saved prompt sequence : answers don't match saved answers",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var username string
+			var port int
+			var ips []string
+
+			p := newSuspendFlow(bytes.NewBufferString("user\n10000\n"), &bytes.Buffer{}, &username, &port, &ips)
+			state := s.state(&p)
+
+			r := newSuspendFlow(nil, nil, &username, &port, &ips)
+			s.setup(&r)
+
+			assert.EqualError(t, r.Resume(strings.NewReader(state)), s.err)
+		})
+	}
+}
+
+func TestPromptsFingerprint(t *testing.T) {
+	type scenario struct {
+		name  string
+		setup func(p *Prompts)
+		same  bool
+	}
+
+	scenarios := []scenario{
+		{
+			"Same definition",
+			func(p *Prompts) {},
+			true,
+		},
+		{
+			"Transition added",
+			func(p *Prompts) {
+				p.AddLinePrompter(&StepPrompt{"name", map[string]string{"admin": "rights", "guest": "age"}, "age"})
+			},
+			false,
+		},
+		{
+			"Transitions in another order",
+			func(p *Prompts) {
+				p.AddLinePrompter(&StepPrompt{"rights", map[string]string{"all": "age"}, "name"})
+			},
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := newProgressFlow()
+			p.AddLinePrompter(&StepPrompt{"rights", map[string]string{"all": "name"}, "age"})
+			r := newProgressFlow()
+			r.AddLinePrompter(&StepPrompt{"rights", map[string]string{"all": "name"}, "age"})
+			s.setup(&r)
+
+			assert.Equal(t, s.same, p.Fingerprint() == r.Fingerprint())
+		})
+	}
+}